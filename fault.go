@@ -0,0 +1,132 @@
+package httpmock
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+var ErrFaultUnsupported = errors.New("response writer does not support faults")
+
+// Fault is a network failure that a [Response] simulates instead of writing a
+// well-formed response.
+type Fault int
+
+const (
+	// FaultNone writes a well-formed response. This is the default.
+	FaultNone Fault = iota
+
+	// FaultCloseConnection closes the connection without writing a response.
+	FaultCloseConnection
+
+	// FaultResetMidBody writes the status line, headers, and half of the body,
+	// then resets the connection.
+	FaultResetMidBody
+
+	// FaultShortBody writes the entire body, but declares a Content-Length
+	// that is larger than the body, then closes the connection.
+	FaultShortBody
+
+	// FaultMalformedHeaders writes a status line followed by a header that
+	// cannot be parsed, then closes the connection.
+	FaultMalformedHeaders
+
+	// FaultHang never writes a response. The connection is closed once the
+	// request's context is done, such as when the client gives up or the
+	// [Server] is closed.
+	FaultHang
+)
+
+// String returns the name of the [Fault].
+func (f Fault) String() string {
+	switch f {
+	case FaultNone:
+		return "FaultNone"
+	case FaultCloseConnection:
+		return "FaultCloseConnection"
+	case FaultResetMidBody:
+		return "FaultResetMidBody"
+	case FaultShortBody:
+		return "FaultShortBody"
+	case FaultMalformedHeaders:
+		return "FaultMalformedHeaders"
+	case FaultHang:
+		return "FaultHang"
+	}
+	return fmt.Sprintf("Fault(%d)", int(f))
+}
+
+// writeFault simulates a [Fault] on the connection underlying a
+// [http.ResponseWriter]. The [http.ResponseWriter] must implement
+// [http.Hijacker].
+func writeFault(ctx context.Context, w http.ResponseWriter, fault Fault, statusCode int, header http.Header, body []byte) (int, error) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrFaultUnsupported, fault)
+	}
+
+	if fault == FaultHang {
+		<-ctx.Done()
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %v", ErrFaultUnsupported, fault, err)
+	}
+
+	switch fault {
+	case FaultResetMidBody:
+		writeRawHead(rw.Writer, statusCode, header, len(body))
+		n, _ := rw.Write(body[:len(body)/2])
+		_ = rw.Flush()
+		resetConn(conn)
+		return n, nil
+
+	case FaultShortBody:
+		writeRawHead(rw.Writer, statusCode, header, len(body)+1)
+		n, _ := rw.Write(body)
+		_ = rw.Flush()
+		_ = conn.Close()
+		return n, nil
+
+	case FaultMalformedHeaders:
+		fmt.Fprintf(rw, "HTTP/1.1 %d %s\r\n", statusCode, http.StatusText(statusCode))
+		fmt.Fprint(rw, "this is not a header\r\n\r\n")
+		_ = rw.Flush()
+	}
+
+	_ = conn.Close()
+	return 0, nil
+}
+
+// writeRawHead writes an HTTP/1.1 status line and headers, including the
+// provided Content-Length, to a hijacked connection.
+func writeRawHead(w *bufio.Writer, statusCode int, header http.Header, contentLength int) {
+	fmt.Fprintf(w, "HTTP/1.1 %d %s\r\n", statusCode, http.StatusText(statusCode))
+
+	header = header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Length", fmt.Sprint(contentLength))
+	_ = header.Write(w)
+
+	fmt.Fprint(w, "\r\n")
+}
+
+// resetConn closes a connection so that the peer receives a TCP reset rather
+// than an orderly shutdown. A TLS connection is reset without sending a
+// close_notify alert.
+func resetConn(conn net.Conn) {
+	if tc, ok := conn.(*tls.Conn); ok {
+		conn = tc.NetConn()
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	_ = conn.Close()
+}
@@ -0,0 +1,132 @@
+package httpmock
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFault_String(t *testing.T) {
+	assert.Equal(t, "FaultNone", FaultNone.String())
+	assert.Equal(t, "FaultCloseConnection", FaultCloseConnection.String())
+	assert.Equal(t, "FaultResetMidBody", FaultResetMidBody.String())
+	assert.Equal(t, "FaultShortBody", FaultShortBody.String())
+	assert.Equal(t, "FaultMalformedHeaders", FaultMalformedHeaders.String())
+	assert.Equal(t, "FaultHang", FaultHang.String())
+	assert.Equal(t, "Fault(42)", Fault(42).String())
+}
+
+func TestResponse_Write_FaultUnsupported(t *testing.T) {
+	// Setup
+	response := &Response{
+		parent:     &Request{parent: new(Mock).Test(t)},
+		statusCode: http.StatusOK,
+		fault:      FaultCloseConnection,
+	}
+
+	recorder := httptest.NewRecorder()
+
+	// Test
+	gotN, gotErr := response.Write(recorder, nil)
+
+	// Assertions
+	assert.ErrorIs(t, gotErr, ErrFaultUnsupported)
+	assert.Zero(t, gotN)
+}
+
+func TestServer_defaultHandler_Fault(t *testing.T) {
+	tests := []struct {
+		name      string
+		fault     Fault
+		wantError string
+	}{
+		{
+			name:      "close-connection",
+			fault:     FaultCloseConnection,
+			wantError: "EOF",
+		},
+		{
+			name:      "reset-mid-body",
+			fault:     FaultResetMidBody,
+			wantError: "",
+		},
+		{
+			name:      "short-body",
+			fault:     FaultShortBody,
+			wantError: "unexpected EOF",
+		},
+		{
+			name:      "malformed-headers",
+			fault:     FaultMalformedHeaders,
+			wantError: "malformed",
+		},
+	}
+
+	for _, useTLS := range []bool{false, true} {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s-tls-%t", tt.name, useTLS), func(t *testing.T) {
+				// Setup
+				mockT := new(MockTestingT)
+				s := NewServerWithConfig(ServerConfig{TLS: useTLS})
+				defer s.Close()
+				s.Mock.Test(mockT)
+				s.On(http.MethodGet, "/foo", nil).RespondOK([]byte(testLongBody)).Fault(tt.fault)
+
+				// Test
+				var gotErr error
+				got, err := s.Client().Get(fmt.Sprintf("%s/foo", s.URL))
+				if err != nil {
+					gotErr = err
+				} else {
+					_, gotErr = io.ReadAll(got.Body)
+					got.Body.Close()
+				}
+
+				// Assertions
+				assert.Error(t, gotErr)
+				assert.ErrorContains(t, gotErr, tt.wantError)
+				assert.Zero(t, mockT.errorfCount)
+				assert.Zero(t, mockT.failNowCount)
+			})
+		}
+	}
+}
+
+func TestServer_defaultHandler_FaultHang(t *testing.T) {
+	// Setup
+	s := NewServer()
+	arrived := make(chan struct{})
+	s.On(http.MethodGet, "/foo", nil).RespondFault(FaultHang).NotifyOn(arrived)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := s.Client().Get(fmt.Sprintf("%s/foo", s.URL))
+		errs <- err
+	}()
+
+	// Test
+	select {
+	case <-arrived:
+	case err := <-errs:
+		t.Fatalf("unexpected response before server was closed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("request did not arrive")
+	}
+	select {
+	case err := <-errs:
+		t.Fatalf("unexpected response before server was closed: %v", err)
+	default:
+	}
+
+	start := time.Now()
+	s.Close()
+
+	// Assertions
+	assert.Error(t, <-errs)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,191 @@
+package httpmock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"unicode/utf8"
+)
+
+var ErrLoadFixture = errors.New("error loading fixture")
+
+// Exchange is a request and the response that was returned for it, such as
+// one recorded by a [Server] that proxies to an upstream. Exchanges are stored
+// in fixture files with [Server.WriteFixture] and replayed with
+// [Mock.LoadFixture].
+type Exchange struct {
+	// The HTTP method of the request.
+	Method string
+
+	// The URL of the request, containing only the path and query.
+	URL string
+
+	// Headers of the request.
+	Header http.Header
+
+	// Body of the request.
+	Body []byte
+
+	// The HTTP status code of the response.
+	StatusCode int
+
+	// Headers of the response.
+	ResponseHeader http.Header
+
+	// Body of the response.
+	ResponseBody []byte
+}
+
+// exchangeJSON is the representation of an [Exchange] in a fixture file.
+// Bodies are stored as text, unless they are not valid UTF-8, in which case
+// they are stored as base64.
+type exchangeJSON struct {
+	Method               string      `json:"method"`
+	URL                  string      `json:"url"`
+	Header               http.Header `json:"header,omitempty"`
+	Body                 string      `json:"body,omitempty"`
+	BodyEncoding         string      `json:"bodyEncoding,omitempty"`
+	StatusCode           int         `json:"status"`
+	ResponseHeader       http.Header `json:"responseHeader,omitempty"`
+	ResponseBody         string      `json:"responseBody,omitempty"`
+	ResponseBodyEncoding string      `json:"responseBodyEncoding,omitempty"`
+}
+
+// fixture is the representation of a fixture file.
+type fixture struct {
+	Exchanges []Exchange `json:"exchanges"`
+}
+
+// encodeBody encodes a body as text, or as base64 if it is not valid UTF-8.
+// The encoding is returned with the encoded body, and is empty for text.
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+// decodeBody decodes a body encoded by [encodeBody].
+func decodeBody(text string, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		if text == "" {
+			return nil, nil
+		}
+		return []byte(text), nil
+	case "base64":
+		return base64.StdEncoding.DecodeString(text)
+	default:
+		return nil, fmt.Errorf("unsupported body encoding %q", encoding)
+	}
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (e Exchange) MarshalJSON() ([]byte, error) {
+	v := exchangeJSON{
+		Method:         e.Method,
+		URL:            e.URL,
+		Header:         e.Header,
+		StatusCode:     e.StatusCode,
+		ResponseHeader: e.ResponseHeader,
+	}
+	v.Body, v.BodyEncoding = encodeBody(e.Body)
+	v.ResponseBody, v.ResponseBodyEncoding = encodeBody(e.ResponseBody)
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (e *Exchange) UnmarshalJSON(data []byte) error {
+	var v exchangeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	body, err := decodeBody(v.Body, v.BodyEncoding)
+	if err != nil {
+		return fmt.Errorf("failed to decode request body: %w", err)
+	}
+	responseBody, err := decodeBody(v.ResponseBody, v.ResponseBodyEncoding)
+	if err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	*e = Exchange{
+		Method:         v.Method,
+		URL:            v.URL,
+		Header:         v.Header,
+		Body:           body,
+		StatusCode:     v.StatusCode,
+		ResponseHeader: v.ResponseHeader,
+		ResponseBody:   responseBody,
+	}
+	return nil
+}
+
+// writeFixture writes exchanges to a fixture file.
+func writeFixture(w io.Writer, exchanges []Exchange) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fixture{Exchanges: exchanges})
+}
+
+// LoadFixture adds an expectation to the [Mock] for each request in a fixture
+// written by [Server.WriteFixture] or a [Server] with a record file. Each
+// expectation matches the method, URL, and body of the recorded request, and
+// responds with the recorded response. If the same request was recorded more
+// than once, the recorded responses are returned in turn, as if they had been
+// added with [Response.ThenRespond].
+//
+// The expectations are optional, as if they had been added with
+// [Request.Maybe], since a fixture usually stands in for an entire upstream.
+//
+//	f, _ := os.Open("testdata/users.json")
+//	defer f.Close()
+//	err := Mock.LoadFixture(f)
+func (m *Mock) LoadFixture(r io.Reader) error {
+	var f fixture
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return fmt.Errorf("%w: %w", ErrLoadFixture, err)
+	}
+
+	m.addExchanges(f.Exchanges)
+	return nil
+}
+
+// LoadFixtureFile is a convenience method which loads a fixture from a file.
+// See [Mock.LoadFixture].
+func (m *Mock) LoadFixtureFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrLoadFixture, err)
+	}
+	defer f.Close()
+
+	return m.LoadFixture(f)
+}
+
+// addExchanges adds an optional expectation for each distinct request of the
+// exchanges, which returns each of the request's responses in turn.
+func (m *Mock) addExchanges(exchanges []Exchange) {
+	responses := map[string]*Response{}
+	for _, e := range exchanges {
+		key := fmt.Sprintf("%s %s\n%s", e.Method, e.URL, e.Body)
+
+		var response *Response
+		if last, ok := responses[key]; ok {
+			response = last.ThenRespond(e.StatusCode, e.ResponseBody)
+		} else {
+			response = m.On(e.Method, e.URL, e.Body).Maybe().Respond(e.StatusCode, e.ResponseBody)
+		}
+		for name, values := range e.ResponseHeader {
+			if len(values) > 0 {
+				response.Header(name, values[0], values[1:]...)
+			}
+		}
+		responses[key] = response
+	}
+}
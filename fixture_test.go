@@ -0,0 +1,354 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_RecordAndReplay(t *testing.T) {
+	// Setup
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Upstream", r.Header.Get("X-Request-Id"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(r.Method + " " + r.URL.RequestURI() + " " + string(body)))
+	}))
+	defer upstream.Close()
+
+	recordFile := filepath.Join(t.TempDir(), "fixture.json")
+	s := NewServerWithConfigT(t, ServerConfig{Upstream: upstream.URL, RecordFile: recordFile})
+	s.On(http.MethodGet, "/local", nil).RespondOK([]byte("local"))
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+		want   string
+	}{
+		{method: http.MethodGet, path: "/local", want: "local"},
+		{method: http.MethodGet, path: "/users/1234?page=2", want: "GET /users/1234?page=2 "},
+		{method: http.MethodPost, path: "/users", body: testBody, want: "POST /users " + testBody},
+	}
+
+	send := func(t *testing.T, s *Server) {
+		for _, r := range requests {
+			req := mustNewRequest(http.NewRequest(r.method, s.URL+r.path, strings.NewReader(r.body)))
+			req.Header.Set("X-Request-Id", "abc")
+			got, err := s.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotBody, _ := io.ReadAll(got.Body)
+			got.Body.Close()
+
+			assert.Equal(t, r.want, string(gotBody))
+			if r.path != "/local" {
+				assert.Equal(t, http.StatusCreated, got.StatusCode)
+				assert.Equal(t, "abc", got.Header.Get("X-Upstream"))
+			}
+		}
+	}
+
+	// Test
+	send(t, s)
+	s.Close()
+
+	// Assertions
+	exchanges := s.Exchanges()
+	if assert.Len(t, exchanges, 2) {
+		assert.Equal(t, http.MethodGet, exchanges[0].Method)
+		assert.Equal(t, "/users/1234?page=2", exchanges[0].URL)
+		assert.Equal(t, "abc", exchanges[0].Header.Get("X-Request-Id"))
+		assert.Nil(t, exchanges[0].Body)
+		assert.Equal(t, http.MethodPost, exchanges[1].Method)
+		assert.Equal(t, []byte(testBody), exchanges[1].Body)
+		assert.Equal(t, http.StatusCreated, exchanges[1].StatusCode)
+		assert.Equal(t, "abc", exchanges[1].ResponseHeader.Get("X-Upstream"))
+		assert.Equal(t, []byte("POST /users "+testBody), exchanges[1].ResponseBody)
+	}
+	assert.Len(t, s.Mock.UnmatchedRequests(), 2)
+
+	// Replay offline
+	upstream.Close()
+	replay := NewServerT(t)
+	replay.On(http.MethodGet, "/local", nil).RespondOK([]byte("local"))
+	assert.NoError(t, replay.Mock.LoadFixtureFile(recordFile))
+	send(t, replay)
+}
+
+func TestServer_Record_EscapedPath(t *testing.T) {
+	// Setup
+	var gotURI string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURI = r.RequestURI
+	}))
+	defer upstream.Close()
+
+	s := NewServerWithConfigT(t, ServerConfig{Upstream: upstream.URL + "/api/"})
+
+	// Test
+	got, err := s.Client().Get(s.URL + "/files/a%2Fb.txt?v=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Body.Close()
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+	assert.Equal(t, "/api/files/a%2Fb.txt?v=1", gotURI)
+	if exchanges := s.Exchanges(); assert.Len(t, exchanges, 1) {
+		assert.Equal(t, "/files/a%2Fb.txt?v=1", exchanges[0].URL)
+	}
+}
+
+func TestServer_Record_UpstreamFail(t *testing.T) {
+	// Setup
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	upstream.Close()
+
+	mockT := new(MockTestingT)
+	s := NewServerWithConfig(ServerConfig{Upstream: upstream.URL})
+	s.Mock.Test(mockT)
+
+	// Test
+	got, err := s.Client().Get(s.URL + "/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Body.Close()
+	s.Close()
+
+	// Assertions
+	assert.Equal(t, http.StatusBadGateway, got.StatusCode)
+	assert.Empty(t, s.Exchanges())
+	if failures := s.Mock.Failures(); assert.Len(t, failures, 1) {
+		assert.ErrorIs(t, failures[0], ErrUpstream)
+	}
+	assert.Equal(t, 1, mockT.errorfCount)
+}
+
+func TestServer_Upstream_Reset(t *testing.T) {
+	tests := []struct {
+		name  string
+		reset func(m *Mock)
+	}{
+		{
+			name:  "reset",
+			reset: func(m *Mock) { m.Reset() },
+		},
+		{
+			name:  "reset-expectations",
+			reset: func(m *Mock) { m.ResetExpectations() },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("upstream"))
+			}))
+			defer upstream.Close()
+
+			s := NewServerWithConfigT(t, ServerConfig{Upstream: upstream.URL})
+			s.On(http.MethodGet, "/local", nil).RespondOK([]byte("local"))
+
+			// Test
+			tt.reset(s.Mock)
+
+			got, err := s.Client().Get(s.URL + "/local")
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotBody, _ := io.ReadAll(got.Body)
+			got.Body.Close()
+
+			// Assertions
+			assert.Empty(t, s.Mock.Expectations())
+			assert.Equal(t, http.StatusOK, got.StatusCode)
+			assert.Equal(t, "upstream", string(gotBody))
+			assert.Len(t, s.Mock.UnmatchedRequests(), 1)
+		})
+	}
+}
+
+func TestNewServerWithConfigT_InvalidUpstream(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstream string
+	}{
+		{
+			name:     "unparsable",
+			upstream: "http://test.com/%zz",
+		},
+		{
+			name:     "missing-scheme",
+			upstream: "test.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			mockT := new(MockTB)
+
+			// Test and Assertions
+			assert.PanicsWithValue(t, "FailNow was called", func() {
+				NewServerWithConfigT(mockT, ServerConfig{Upstream: tt.upstream})
+			})
+			assert.Equal(t, 1, mockT.errorfCount)
+			assert.Empty(t, mockT.cleanups)
+		})
+	}
+}
+
+func TestServer_WriteFixture(t *testing.T) {
+	// Setup
+	s := &Server{Mock: new(Mock), exchanges: []Exchange{
+		{Method: http.MethodGet, URL: "/users", StatusCode: http.StatusOK, ResponseBody: []byte(`[]`)},
+	}}
+
+	// Test
+	var buf bytes.Buffer
+	err := s.WriteFixture(&buf)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"exchanges": [{"method": "GET", "url": "/users", "status": 200, "responseBody": "[]"}]}`, buf.String())
+}
+
+func TestExchange_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		exchange Exchange
+		wantJSON string
+	}{
+		{
+			name:     "empty-bodies",
+			exchange: Exchange{Method: http.MethodDelete, URL: "/users/1234", StatusCode: http.StatusNoContent},
+			wantJSON: `{"method": "DELETE", "url": "/users/1234", "status": 204}`,
+		},
+		{
+			name: "text-bodies",
+			exchange: Exchange{
+				Method:         http.MethodPost,
+				URL:            "/users",
+				Header:         http.Header{"Content-Type": {"application/json"}},
+				Body:           []byte(`{"name": "foo"}`),
+				StatusCode:     http.StatusCreated,
+				ResponseHeader: http.Header{"Location": {"/users/1234"}},
+				ResponseBody:   []byte(`{"id": "1234"}`),
+			},
+			wantJSON: `{"method": "POST", "url": "/users", "header": {"Content-Type": ["application/json"]}, "body": "{\"name\": \"foo\"}", "status": 201, "responseHeader": {"Location": ["/users/1234"]}, "responseBody": "{\"id\": \"1234\"}"}`,
+		},
+		{
+			name:     "binary-body",
+			exchange: Exchange{Method: http.MethodGet, URL: "/image", StatusCode: http.StatusOK, ResponseBody: []byte{0xff, 0xd8, 0xff}},
+			wantJSON: `{"method": "GET", "url": "/image", "status": 200, "responseBody": "/9j/", "responseBodyEncoding": "base64"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			gotJSON, err := json.Marshal(tt.exchange)
+			assert.NoError(t, err)
+
+			var got Exchange
+			err = json.Unmarshal(gotJSON, &got)
+
+			// Assertions
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.wantJSON, string(gotJSON))
+			assert.Equal(t, tt.exchange, got)
+		})
+	}
+}
+
+func TestExchange_UnmarshalJSON_Fail(t *testing.T) {
+	// Test
+	var got Exchange
+	err := json.Unmarshal([]byte(`{"method": "GET", "url": "/", "status": 200, "responseBody": "abc", "responseBodyEncoding": "gzip"}`), &got)
+
+	// Assertions
+	assert.ErrorContains(t, err, `unsupported body encoding "gzip"`)
+}
+
+func TestMock_LoadFixture(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	fixture := `{"exchanges": [
+		{"method": "GET", "url": "/jobs/1234", "status": 202, "responseHeader": {"Retry-After": ["1"]}},
+		{"method": "POST", "url": "/jobs", "body": "{}", "status": 201, "responseBody": "{\"id\": \"1234\"}"},
+		{"method": "GET", "url": "/jobs/1234", "status": 200, "responseBody": "done"}
+	]}`
+
+	// Test
+	err := m.LoadFixture(strings.NewReader(fixture))
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Len(t, m.Expectations(), 2)
+
+	var got []string
+	for range 3 {
+		received := mustNewRequest(http.NewRequest(http.MethodGet, "/jobs/1234", http.NoBody))
+		recorder := httptest.NewRecorder()
+		_, err := m.Requested(received).Write(recorder, received)
+		assert.NoError(t, err)
+		got = append(got, recorder.Result().Status+" "+recorder.Header().Get("Retry-After")+" "+recorder.Body.String())
+	}
+	assert.Equal(t, []string{"202 Accepted 1 ", "200 OK  done", "200 OK  done"}, got)
+
+	// The unused POST expectation is optional
+	m.AssertExpectations(t)
+}
+
+func TestMock_LoadFixture_PartialReplay(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	m := new(Mock).Test(mockT)
+	fixture := `{"exchanges": [
+		{"method": "GET", "url": "/jobs/1234", "status": 202},
+		{"method": "GET", "url": "/jobs/1234", "status": 202},
+		{"method": "GET", "url": "/jobs/1234", "status": 200, "responseBody": "done"}
+	]}`
+	assert.NoError(t, m.LoadFixture(strings.NewReader(fixture)))
+
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "/jobs/1234", http.NoBody))
+	recorder := httptest.NewRecorder()
+	_, err := m.Requested(received).Write(recorder, received)
+	assert.NoError(t, err)
+
+	// Test
+	got := m.AssertExpectations(mockT)
+
+	// Assertions
+	assert.True(t, got)
+	assert.Zero(t, mockT.errorfCount)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+}
+
+func TestMock_LoadFixture_Fail(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+
+	// Test
+	err := m.LoadFixture(strings.NewReader(`{"exchanges": [`))
+	errFile := m.LoadFixtureFile(filepath.Join(t.TempDir(), "missing.json"))
+
+	// Assertions
+	assert.ErrorIs(t, err, ErrLoadFixture)
+	assert.ErrorIs(t, errFile, ErrLoadFixture)
+	assert.ErrorIs(t, errFile, os.ErrNotExist)
+	assert.Empty(t, m.Expectations())
+}
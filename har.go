@@ -0,0 +1,379 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrLoadHAR = errors.New("error loading HAR")
+
+// harVersion is the version of the HAR format written by [Mock.ExportHAR].
+const harVersion = "1.2"
+
+// harFile is the root of a HAR file. See
+// http://www.softwareishard.com/blog/har-12-spec/ for the specification.
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+	Comment     string         `json:"comment,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// harPostData is the body of a request. Encoding is not part of the HAR
+// specification, but is written by some tools for bodies that are not valid
+// UTF-8, in the same way as [harContent].
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Encoding string         `json:"encoding,omitempty"`
+	Params   []harNameValue `json:"params,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harSkipResponseHeaders are response headers that are not loaded from a HAR
+// file, since HAR files contain decoded bodies.
+var harSkipResponseHeaders = []string{
+	"Content-Encoding",
+	"Content-Length",
+}
+
+// LoadHAR adds an expectation to the [Mock] for each entry in a HAR file, such
+// as one captured by a browser or proxy. Each expectation matches the method,
+// path, query, and body of the entry's request, and responds with the entry's
+// response. The scheme and host of the entry's URL are ignored, so that the
+// expectations may be used with a [Server] or [Transport]. As with
+// [Mock.LoadFixture], the expectations are optional, and an entry's request
+// that appears more than once returns each of its responses in turn.
+//
+// Entries without a response, such as requests that were blocked by the
+// browser, are skipped. Pseudo-headers, and the Content-Encoding and
+// Content-Length response headers, are not loaded.
+//
+//	f, _ := os.Open("testdata/checkout.har")
+//	defer f.Close()
+//	err := Mock.LoadHAR(f)
+func (m *Mock) LoadHAR(r io.Reader) error {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return fmt.Errorf("%w: %w", ErrLoadHAR, err)
+	}
+
+	exchanges := make([]Exchange, 0, len(har.Log.Entries))
+	for i, entry := range har.Log.Entries {
+		if entry.Response.Status == 0 {
+			continue
+		}
+
+		exchange, err := entry.exchange()
+		if err != nil {
+			return fmt.Errorf("%w: entry %d: %w", ErrLoadHAR, i, err)
+		}
+		exchanges = append(exchanges, exchange)
+	}
+
+	m.addExchanges(exchanges)
+	return nil
+}
+
+// exchange converts a HAR entry to an [Exchange].
+func (e harEntry) exchange() (Exchange, error) {
+	u, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return Exchange{}, err
+	}
+
+	var body []byte
+	if postData := e.Request.PostData; postData != nil {
+		text := postData.Text
+		if text == "" && len(postData.Params) > 0 {
+			params := url.Values{}
+			for _, p := range postData.Params {
+				params.Add(p.Name, p.Value)
+			}
+			text = params.Encode()
+		}
+		if body, err = decodeBody(text, postData.Encoding); err != nil {
+			return Exchange{}, fmt.Errorf("failed to decode request body: %w", err)
+		}
+	}
+
+	responseBody, err := decodeBody(e.Response.Content.Text, e.Response.Content.Encoding)
+	if err != nil {
+		return Exchange{}, fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	responseHeader := harHeader(e.Response.Headers)
+	for _, key := range harSkipResponseHeaders {
+		responseHeader.Del(key)
+	}
+	removeHopHeaders(responseHeader)
+
+	return Exchange{
+		Method:         e.Request.Method,
+		URL:            u.RequestURI(),
+		Header:         harHeader(e.Request.Headers),
+		Body:           body,
+		StatusCode:     e.Response.Status,
+		ResponseHeader: responseHeader,
+		ResponseBody:   responseBody,
+	}, nil
+}
+
+// harHeader converts HAR headers to a [http.Header], skipping HTTP/2
+// pseudo-headers such as ":authority".
+func harHeader(headers []harNameValue) http.Header {
+	h := http.Header{}
+	for _, header := range headers {
+		if strings.HasPrefix(header.Name, ":") {
+			continue
+		}
+		h.Add(header.Name, header.Value)
+	}
+	return h
+}
+
+// ExportHAR writes the requests that have been received by the [Mock], and the
+// responses that they were matched to, as a HAR 1.2 file that may be inspected
+// with standard tools, such as a browser's developer tools.
+//
+// The response of each entry is the response that was written by the default
+// [Server] handler or [Transport], including responses written by a custom
+// [ResponseWriter] and bodies rendered from a template. For requests that were
+// passed to [Mock.Requested] directly, or whose response was replaced by a
+// [Fault], the response configured on the matched expectation is used instead,
+// and any part of it that is not available is noted by a comment on the
+// entry's response. Timings are not recorded, and are always 0.
+//
+//	f, _ := os.Create("requests.har")
+//	defer f.Close()
+//	err := Mock.ExportHAR(f)
+func (m *Mock) ExportHAR(w io.Writer) error {
+	calls := m.Calls()
+
+	har := harFile{
+		Log: harLog{
+			Version: harVersion,
+			Creator: harCreator{Name: "httpmock", Version: moduleVersion()},
+			Entries: make([]harEntry, 0, len(calls)),
+		},
+	}
+	for _, call := range calls {
+		har.Log.Entries = append(har.Log.Entries, newHAREntry(call))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(har)
+}
+
+// newHAREntry converts a received [Request] and its response to a HAR entry.
+func newHAREntry(r Request) harEntry {
+	httpVersion := r.proto
+	if httpVersion == "" {
+		httpVersion = "HTTP/1.1"
+	}
+
+	entry := harEntry{
+		StartedDateTime: r.receivedAt,
+		Request: harRequest{
+			Method:      r.method,
+			URL:         r.absoluteURL(),
+			HTTPVersion: httpVersion,
+			Cookies:     harCookies((&http.Request{Header: r.header}).Cookies()),
+			Headers:     harNameValues(r.header),
+			QueryString: harNameValues(r.url.Query()),
+			HeadersSize: -1,
+			BodySize:    len(r.body),
+		},
+		Response: newHARResponse(r.response, r.written, httpVersion),
+	}
+	if len(r.body) > 0 {
+		entry.Request.PostData = &harPostData{MimeType: r.header.Get("Content-Type")}
+		entry.Request.PostData.Text, entry.Request.PostData.Encoding = encodeBody(r.body)
+	}
+	if r.unmatched {
+		entry.Comment = "Handled by the fallback expectation set with Mock.OnUnmatched."
+	}
+	return entry
+}
+
+// newHARResponse converts the response that was written for a request to a
+// HAR response. If the written response was not captured, the [Response]
+// configured on the matched expectation is converted instead.
+func newHARResponse(r *Response, written *writtenResponse, httpVersion string) harResponse {
+	resp := harResponse{
+		HTTPVersion: httpVersion,
+		Cookies:     []harNameValue{},
+		Headers:     []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+
+	if written != nil {
+		resp.setHead(written.statusCode, written.header)
+		resp.setBody(written.body)
+		return resp
+	}
+
+	switch {
+	case r == nil:
+		resp.Comment = "No response was configured."
+		return resp
+	case r.writer != nil:
+		resp.Comment = "The response was written by a custom ResponseWriter and was not captured."
+		return resp
+	}
+
+	resp.setHead(r.statusCode, r.header)
+
+	switch {
+	case r.template != nil:
+		resp.Comment = "The response body was rendered from a template and was not captured."
+	case r.fault != FaultNone:
+		resp.Comment = fmt.Sprintf("The response was replaced by the fault %s.", r.fault)
+	default:
+		resp.setBody(r.body)
+	}
+	return resp
+}
+
+// setHead sets the status and headers of a HAR response.
+func (resp *harResponse) setHead(statusCode int, header http.Header) {
+	resp.Status = statusCode
+	resp.StatusText = http.StatusText(statusCode)
+	resp.Cookies = harCookies((&http.Response{Header: header}).Cookies())
+	resp.Headers = harNameValues(header)
+	resp.RedirectURL = header.Get("Location")
+	resp.Content.MimeType = header.Get("Content-Type")
+}
+
+// setBody sets the body of a HAR response.
+func (resp *harResponse) setBody(body []byte) {
+	resp.BodySize = len(body)
+	resp.Content.Size = len(body)
+	resp.Content.Text, resp.Content.Encoding = encodeBody(body)
+}
+
+// absoluteURL returns the URL of a received [Request], including the scheme
+// and host that it was received with if the URL does not contain them.
+func (r *Request) absoluteURL() string {
+	u := *r.url
+	if u.Host == "" && r.host != "" {
+		u.Host = r.host
+	}
+	if u.Scheme == "" && u.Host != "" {
+		u.Scheme = "http"
+		if r.tls != nil {
+			u.Scheme = "https"
+		}
+	}
+	return u.String()
+}
+
+// harNameValues converts headers or query values to HAR name-value pairs,
+// sorted by name.
+func harNameValues(values map[string][]string) []harNameValue {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	pairs := []harNameValue{}
+	for _, name := range names {
+		for _, value := range values[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+	return pairs
+}
+
+// harCookies converts cookies to HAR name-value pairs.
+func harCookies(cookies []*http.Cookie) []harNameValue {
+	pairs := []harNameValue{}
+	for _, c := range cookies {
+		pairs = append(pairs, harNameValue{Name: c.Name, Value: c.Value})
+	}
+	return pairs
+}
+
+// moduleVersion returns the version of this module, as recorded in the build
+// information of the running binary.
+func moduleVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/shawalli/httpmock" {
+				return dep.Version
+			}
+		}
+	}
+	return "(devel)"
+}
@@ -0,0 +1,521 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testHAR = `{
+  "log": {
+    "version": "1.2",
+    "creator": {"name": "WebInspector", "version": "537.36"},
+    "entries": [
+      {
+        "startedDateTime": "2024-01-02T03:04:05.000Z",
+        "time": 12.5,
+        "request": {
+          "method": "GET",
+          "url": "https://api.example.com/users/1234?fields=name",
+          "httpVersion": "http/2.0",
+          "headers": [{"name": ":authority", "value": "api.example.com"}, {"name": "accept", "value": "application/json"}],
+          "queryString": [{"name": "fields", "value": "name"}],
+          "cookies": [],
+          "headersSize": -1,
+          "bodySize": 0
+        },
+        "response": {
+          "status": 200,
+          "statusText": "",
+          "httpVersion": "http/2.0",
+          "headers": [
+            {"name": "content-type", "value": "application/json"},
+            {"name": "content-encoding", "value": "gzip"},
+            {"name": "content-length", "value": "31"}
+          ],
+          "cookies": [],
+          "content": {"size": 16, "mimeType": "application/json", "text": "{\"name\": \"foo\"}"},
+          "redirectURL": "",
+          "headersSize": -1,
+          "bodySize": 31
+        },
+        "cache": {},
+        "timings": {"send": 1, "wait": 10, "receive": 1.5}
+      },
+      {
+        "startedDateTime": "2024-01-02T03:04:06.000Z",
+        "time": 0,
+        "request": {
+          "method": "POST",
+          "url": "https://api.example.com/login",
+          "httpVersion": "HTTP/1.1",
+          "headers": [{"name": "Content-Type", "value": "application/x-www-form-urlencoded"}],
+          "queryString": [],
+          "cookies": [],
+          "postData": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "user", "value": "foo"}]},
+          "headersSize": -1,
+          "bodySize": 8
+        },
+        "response": {
+          "status": 204,
+          "statusText": "No Content",
+          "httpVersion": "HTTP/1.1",
+          "headers": [{"name": "Set-Cookie", "value": "session=abc"}],
+          "cookies": [{"name": "session", "value": "abc"}],
+          "content": {"size": 0, "mimeType": ""},
+          "redirectURL": "",
+          "headersSize": -1,
+          "bodySize": 0
+        },
+        "cache": {},
+        "timings": {"send": 0, "wait": 0, "receive": 0}
+      },
+      {
+        "startedDateTime": "2024-01-02T03:04:07.000Z",
+        "time": 0,
+        "request": {
+          "method": "GET",
+          "url": "https://api.example.com/logo.png",
+          "httpVersion": "HTTP/1.1",
+          "headers": [],
+          "queryString": [],
+          "cookies": [],
+          "headersSize": -1,
+          "bodySize": 0
+        },
+        "response": {
+          "status": 200,
+          "statusText": "OK",
+          "httpVersion": "HTTP/1.1",
+          "headers": [{"name": "Content-Type", "value": "image/png"}],
+          "cookies": [],
+          "content": {"size": 3, "mimeType": "image/png", "text": "iVBO", "encoding": "base64"},
+          "redirectURL": "",
+          "headersSize": -1,
+          "bodySize": 3
+        },
+        "cache": {},
+        "timings": {"send": 0, "wait": 0, "receive": 0}
+      },
+      {
+        "startedDateTime": "2024-01-02T03:04:08.000Z",
+        "time": 0,
+        "request": {
+          "method": "GET",
+          "url": "https://ads.example.com/track",
+          "httpVersion": "",
+          "headers": [],
+          "queryString": [],
+          "cookies": [],
+          "headersSize": -1,
+          "bodySize": 0
+        },
+        "response": {
+          "status": 0,
+          "statusText": "",
+          "httpVersion": "",
+          "headers": [],
+          "cookies": [],
+          "content": {"size": 0, "mimeType": "x-unknown"},
+          "redirectURL": "",
+          "headersSize": -1,
+          "bodySize": -1,
+          "_error": "net::ERR_BLOCKED_BY_CLIENT"
+        },
+        "cache": {},
+        "timings": {"send": 0, "wait": 0, "receive": 0}
+      }
+    ]
+  }
+}`
+
+func TestMock_LoadHAR(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+
+	// Test
+	err := m.LoadHAR(strings.NewReader(testHAR))
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Len(t, m.Expectations(), 3)
+
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		wantStatus int
+		wantHeader http.Header
+		wantBody   []byte
+	}{
+		{
+			name:       "json",
+			method:     http.MethodGet,
+			url:        "/users/1234?fields=name",
+			wantStatus: http.StatusOK,
+			wantHeader: http.Header{"Content-Type": {"application/json"}},
+			wantBody:   []byte(`{"name": "foo"}`),
+		},
+		{
+			name:       "form-params",
+			method:     http.MethodPost,
+			url:        "/login",
+			body:       "user=foo",
+			wantStatus: http.StatusNoContent,
+			wantHeader: http.Header{"Set-Cookie": {"session=abc"}},
+		},
+		{
+			name:       "base64",
+			method:     http.MethodGet,
+			url:        "/logo.png",
+			wantStatus: http.StatusOK,
+			wantHeader: http.Header{"Content-Type": {"image/png"}},
+			wantBody:   []byte{0x89, 0x50, 0x4e},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			received := mustNewRequest(http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body)))
+			recorder := httptest.NewRecorder()
+
+			// Test
+			_, err := m.Requested(received).Write(recorder, received)
+
+			// Assertions
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assert.Equal(t, tt.wantHeader, recorder.Header())
+			assert.Equal(t, tt.wantBody, recorder.Body.Bytes())
+		})
+	}
+}
+
+func TestMock_LoadHAR_PartialReplay(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	m := new(Mock).Test(mockT)
+	har := `{"log": {"entries": [
+		{"request": {"method": "GET", "url": "https://api.example.com/jobs/1234"}, "response": {"status": 202, "content": {}}},
+		{"request": {"method": "GET", "url": "https://api.example.com/jobs/1234"}, "response": {"status": 200, "content": {"text": "done"}}}
+	]}}`
+	assert.NoError(t, m.LoadHAR(strings.NewReader(har)))
+
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "/jobs/1234", http.NoBody))
+	recorder := httptest.NewRecorder()
+	_, err := m.Requested(received).Write(recorder, received)
+	assert.NoError(t, err)
+
+	// Test
+	got := m.AssertExpectations(mockT)
+
+	// Assertions
+	assert.True(t, got)
+	assert.Zero(t, mockT.errorfCount)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+}
+
+func TestMock_LoadHAR_Fail(t *testing.T) {
+	tests := []struct {
+		name    string
+		har     string
+		wantErr string
+	}{
+		{
+			name:    "invalid-json",
+			har:     `{"log": {"entries": [`,
+			wantErr: "error loading HAR: unexpected EOF",
+		},
+		{
+			name:    "invalid-encoding",
+			har:     `{"log": {"entries": [{"request": {"method": "GET", "url": "/"}, "response": {"status": 200, "content": {"text": "abc", "encoding": "gzip"}}}]}}`,
+			wantErr: `error loading HAR: entry 0: failed to decode response body: unsupported body encoding "gzip"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock).Test(t)
+
+			// Test
+			err := m.LoadHAR(strings.NewReader(tt.har))
+
+			// Assertions
+			assert.ErrorIs(t, err, ErrLoadHAR)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Empty(t, m.Expectations())
+		})
+	}
+}
+
+func TestMock_ExportHAR(t *testing.T) {
+	// Setup
+	s := NewServerT(t)
+	s.Mock.TolerateUnmatched()
+	s.On(http.MethodGet, "/users/1234", nil).
+		RespondJSON(http.StatusOK, map[string]string{"name": "foo"}).
+		Header("Set-Cookie", "session=abc")
+	s.On(http.MethodPost, "/users", []byte(testBody)).Respond(http.StatusCreated, nil).Header("Location", "/users/1234")
+	s.On(http.MethodGet, "/status", nil).RespondUsing(func(w http.ResponseWriter, _ *http.Request) (int, error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		return w.Write([]byte("pending"))
+	})
+	s.On(http.MethodGet, "/hello/{name}", nil).RespondTemplate(http.StatusOK, `hello {{.PathValue "name"}}`)
+	s.Mock.OnUnmatched().Respond(http.StatusNotFound, nil)
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: http.MethodGet, path: "/users/1234?fields=name&fields=id"},
+		{method: http.MethodPost, path: "/users", body: testBody},
+		{method: http.MethodGet, path: "/status"},
+		{method: http.MethodGet, path: "/hello/foo"},
+		{method: http.MethodGet, path: "/missing"},
+	}
+	for _, r := range requests {
+		req := mustNewRequest(http.NewRequest(r.method, s.URL+r.path, strings.NewReader(r.body)))
+		req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+		resp, err := s.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	// Test
+	var buf bytes.Buffer
+	err := s.Mock.ExportHAR(&buf)
+
+	// Assertions
+	assert.NoError(t, err)
+
+	var got harFile
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "1.2", got.Log.Version)
+	assert.Equal(t, "httpmock", got.Log.Creator.Name)
+	if !assert.Len(t, got.Log.Entries, 5) {
+		return
+	}
+
+	get := got.Log.Entries[0]
+	assert.False(t, get.StartedDateTime.IsZero())
+	assert.Equal(t, http.MethodGet, get.Request.Method)
+	assert.Equal(t, s.URL+"/users/1234?fields=name&fields=id", get.Request.URL)
+	assert.Equal(t, "HTTP/1.1", get.Request.HTTPVersion)
+	assert.Equal(t, []harNameValue{{Name: "fields", Value: "name"}, {Name: "fields", Value: "id"}}, get.Request.QueryString)
+	assert.Equal(t, []harNameValue{{Name: "theme", Value: "dark"}}, get.Request.Cookies)
+	assert.Contains(t, get.Request.Headers, harNameValue{Name: "Cookie", Value: "theme=dark"})
+	assert.Nil(t, get.Request.PostData)
+	assert.Equal(t, http.StatusOK, get.Response.Status)
+	assert.Equal(t, "OK", get.Response.StatusText)
+	assert.Equal(t, []harNameValue{{Name: "Content-Type", Value: "application/json"}, {Name: "Set-Cookie", Value: "session=abc"}}, get.Response.Headers)
+	assert.Equal(t, []harNameValue{{Name: "session", Value: "abc"}}, get.Response.Cookies)
+	assert.Equal(t, harContent{Size: 14, MimeType: "application/json", Text: `{"name":"foo"}`}, get.Response.Content)
+
+	post := got.Log.Entries[1]
+	assert.Equal(t, &harPostData{Text: testBody}, post.Request.PostData)
+	assert.Equal(t, len(testBody), post.Request.BodySize)
+	assert.Equal(t, http.StatusCreated, post.Response.Status)
+	assert.Equal(t, "/users/1234", post.Response.RedirectURL)
+
+	writer := got.Log.Entries[2]
+	assert.Equal(t, http.StatusAccepted, writer.Response.Status)
+	assert.Equal(t, []harNameValue{{Name: "Content-Type", Value: "text/plain"}}, writer.Response.Headers)
+	assert.Equal(t, harContent{Size: 7, MimeType: "text/plain", Text: "pending"}, writer.Response.Content)
+	assert.Empty(t, writer.Response.Comment)
+
+	template := got.Log.Entries[3]
+	assert.Equal(t, http.StatusOK, template.Response.Status)
+	assert.Equal(t, "hello foo", template.Response.Content.Text)
+	assert.Empty(t, template.Response.Comment)
+
+	unmatched := got.Log.Entries[4]
+	assert.Equal(t, http.StatusNotFound, unmatched.Response.Status)
+	assert.Contains(t, unmatched.Comment, "OnUnmatched")
+
+	// Round trip
+	replay := new(Mock).Test(t)
+	assert.NoError(t, replay.LoadHAR(&buf))
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "/users/1234?fields=name&fields=id", http.NoBody))
+	recorder := httptest.NewRecorder()
+	_, err = replay.Requested(received).Write(recorder, received)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"foo"}`, recorder.Body.String())
+}
+
+func TestMock_ExportHAR_NotCaptured(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(http.MethodGet, "/users", nil).RespondOK([]byte(`[]`))
+	m.On(http.MethodGet, "/status", nil).RespondUsing(func(w http.ResponseWriter, _ *http.Request) (int, error) {
+		return w.Write([]byte("ok"))
+	})
+	m.On(http.MethodGet, "/hello", nil).RespondTemplate(http.StatusOK, "hello {{.Method}}")
+
+	for _, path := range []string{"/users", "/status", "/hello"} {
+		m.Requested(mustNewRequest(http.NewRequest(http.MethodGet, path, http.NoBody)))
+	}
+
+	// Test
+	var buf bytes.Buffer
+	err := m.ExportHAR(&buf)
+
+	// Assertions
+	assert.NoError(t, err)
+
+	var got harFile
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, got.Log.Entries, 3) {
+		return
+	}
+
+	configured := got.Log.Entries[0].Response
+	assert.Equal(t, http.StatusOK, configured.Status)
+	assert.Equal(t, "[]", configured.Content.Text)
+	assert.Empty(t, configured.Comment)
+
+	writer := got.Log.Entries[1].Response
+	assert.Zero(t, writer.Status)
+	assert.Contains(t, writer.Comment, "custom ResponseWriter")
+
+	template := got.Log.Entries[2].Response
+	assert.Equal(t, http.StatusOK, template.Status)
+	assert.Empty(t, template.Content.Text)
+	assert.Contains(t, template.Comment, "template")
+}
+
+func TestServer_ExportHAR_ResponseController(t *testing.T) {
+	// Setup
+	s := NewServerT(t)
+	s.On(http.MethodGet, "/events", nil).RespondUsing(func(w http.ResponseWriter, _ *http.Request) (int, error) {
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Now().Add(time.Minute)); err != nil {
+			return 0, err
+		}
+		n, err := w.Write([]byte("event"))
+		if err != nil {
+			return n, err
+		}
+		return n, rc.Flush()
+	})
+
+	resp, err := s.Client().Get(s.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotBody, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// Test
+	var buf bytes.Buffer
+	err = s.Mock.ExportHAR(&buf)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "event", string(gotBody))
+	assert.Empty(t, s.Mock.Failures())
+
+	var got harFile
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, got.Log.Entries, 1) {
+		assert.Equal(t, http.StatusOK, got.Log.Entries[0].Response.Status)
+		assert.Equal(t, "event", got.Log.Entries[0].Response.Content.Text)
+	}
+}
+
+func TestTransport_ExportHAR(t *testing.T) {
+	// Setup
+	tr := NewTransportT(t)
+	tr.On(http.MethodGet, "/status", nil).RespondUsing(func(w http.ResponseWriter, _ *http.Request) (int, error) {
+		w.WriteHeader(http.StatusAccepted)
+		return w.Write([]byte("pending"))
+	})
+
+	resp, err := tr.Client().Get("https://test.com/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// Test
+	var buf bytes.Buffer
+	err = tr.Mock.ExportHAR(&buf)
+
+	// Assertions
+	assert.NoError(t, err)
+
+	var got harFile
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, got.Log.Entries, 1) {
+		assert.Equal(t, http.StatusAccepted, got.Log.Entries[0].Response.Status)
+		assert.Equal(t, "pending", got.Log.Entries[0].Response.Content.Text)
+		assert.Empty(t, got.Log.Entries[0].Response.Comment)
+	}
+}
+
+func TestRequest_absoluteURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		host string
+		want string
+	}{
+		{
+			name: "absolute",
+			url:  "https://test.com/foo?bar=baz",
+			host: "other.com",
+			want: "https://test.com/foo?bar=baz",
+		},
+		{
+			name: "host",
+			url:  "/foo?bar=baz",
+			host: "test.com",
+			want: "http://test.com/foo?bar=baz",
+		},
+		{
+			name: "relative",
+			url:  "/foo",
+			want: "/foo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := &Request{url: u, host: tt.host}
+
+			// Test
+			got := r.absoluteURL()
+
+			// Assertions
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
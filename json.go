@@ -0,0 +1,288 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// jsonIdentifier matches object keys that may be formatted with dot-notation
+// in a JSON path.
+var jsonIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// decodeJSON parses a JSON document, preserving numbers as [json.Number] so that
+// they may be compared without losing precision.
+func decodeJSON(data []byte) (any, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	if d.More() {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+	return v, nil
+}
+
+// formatJSON formats a parsed JSON value for diff output.
+func formatJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// jsonPathKey appends an object key to a JSON path.
+func jsonPathKey(path string, key string) string {
+	if jsonIdentifier.MatchString(key) {
+		return fmt.Sprintf("%s.%s", path, key)
+	}
+	return fmt.Sprintf("%s[%q]", path, key)
+}
+
+// jsonDifference describes a single difference between two JSON documents.
+type jsonDifference struct {
+	// JSON path of the difference, such as `$.items[2].id`.
+	path string
+
+	// Formatted actual value.
+	actual string
+
+	// Formatted expected value.
+	expected string
+}
+
+// diffJSON detects differences between an actual and expected parsed JSON
+// value, ignoring object key order. If allowExtraFields is true, object fields
+// found in the actual value but not the expected value are ignored.
+func diffJSON(path string, actual any, expected any, allowExtraFields bool) []jsonDifference {
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(e)+len(a))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, ok := e[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		var differences []jsonDifference
+		for _, k := range keys {
+			ev, eok := e[k]
+			av, aok := a[k]
+			switch {
+			case !aok:
+				differences = append(differences, jsonDifference{jsonPathKey(path, k), fmtMissing, formatJSON(ev)})
+			case !eok:
+				if !allowExtraFields {
+					differences = append(differences, jsonDifference{jsonPathKey(path, k), formatJSON(av), fmtMissing})
+				}
+			default:
+				differences = append(differences, diffJSON(jsonPathKey(path, k), av, ev, allowExtraFields)...)
+			}
+		}
+		return differences
+
+	case []any:
+		a, ok := actual.([]any)
+		if !ok {
+			break
+		}
+
+		var differences []jsonDifference
+		for i := 0; i < len(e) || i < len(a); i++ {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(a):
+				differences = append(differences, jsonDifference{p, fmtMissing, formatJSON(e[i])})
+			case i >= len(e):
+				differences = append(differences, jsonDifference{p, formatJSON(a[i]), fmtMissing})
+			default:
+				differences = append(differences, diffJSON(p, a[i], e[i], allowExtraFields)...)
+			}
+		}
+		return differences
+
+	case json.Number:
+		if a, ok := actual.(json.Number); ok && equalJSONNumbers(a, e) {
+			return nil
+		}
+
+	default:
+		if reflect.DeepEqual(actual, expected) {
+			return nil
+		}
+	}
+
+	return []jsonDifference{{path, formatJSON(actual), formatJSON(expected)}}
+}
+
+// equalJSONNumbers compares two JSON numbers numerically, so that numbers
+// such as `1` and `1.0` are considered equal.
+func equalJSONNumbers(a json.Number, b json.Number) bool {
+	if a == b {
+		return true
+	}
+
+	af, aok := new(big.Float).SetString(a.String())
+	bf, bok := new(big.Float).SetString(b.String())
+	if !aok || !bok {
+		return false
+	}
+	return af.Cmp(bf) == 0
+}
+
+// matchJSONBody detects differences between a [Request]'s JSON body and a
+// received JSON body. Each difference is included as a child of the result,
+// keyed by its JSON path.
+func (r *Request) matchJSONBody(otherBody []byte) MatchResult {
+	a := fmt.Sprintf("(%d) %s", len(otherBody), trimBody(otherBody))
+
+	expected, err := decodeJSON(r.body)
+	if err != nil {
+		return MatchResult{Field: "Body", Message: fmt.Sprintf("(JSON) expected body unable to be parsed: %v", err), Differences: 1}
+	}
+
+	actual, err := decodeJSON(otherBody)
+	if err != nil {
+		return MatchResult{Field: "Body", Message: fmt.Sprintf("(JSON) %s unable to be parsed: %v", a, err), Differences: 1}
+	}
+
+	differences := diffJSON("$", actual, expected, r.allowExtraFields)
+	if len(differences) == 0 {
+		result := newMatchResult("Body", a, fmt.Sprintf("(%d) %s", len(r.body), trimBody(r.body)), true)
+		result.Message = "(JSON)"
+		return result
+	}
+
+	result := MatchResult{Field: "Body", Message: "(JSON)", Differences: 1}
+	for _, d := range differences {
+		result.Children = append(result.Children, newMatchResult(d.path, d.actual, d.expected, false))
+	}
+	return result
+}
+
+// typedJSONBody matches a received JSON body by decoding it into a Go value.
+type typedJSONBody struct {
+	// Describes the expected value for output, such as `(JSON main.User)`.
+	description string
+
+	// Decodes a received body and matches the decoded value.
+	match func(otherBody []byte) MatchResult
+}
+
+// String returns the description of the expected value.
+func (t *typedJSONBody) String() string {
+	return t.description
+}
+
+// decodeTypedJSON decodes a received JSON body into a value of type T. If the
+// body cannot be decoded, a failing [MatchResult] is returned.
+func decodeTypedJSON[T any](otherBody []byte, description string) (T, MatchResult, bool) {
+	var v T
+	if err := json.Unmarshal(otherBody, &v); err != nil {
+		a := fmt.Sprintf("(%d) %s", len(otherBody), trimBody(otherBody))
+		return v, MatchResult{Field: "Body", Message: fmt.Sprintf("%s %s unable to be decoded: %v", description, a, err), Differences: 1}, false
+	}
+	return v, MatchResult{}, true
+}
+
+// OnJSONValue starts a description of an expectation of the specified
+// [Request] with a JSON body that decodes into a value of type T equal to the
+// provided value. The received body is decoded with [json.Unmarshal], so
+// fields that are not part of T are ignored. A body that cannot be decoded is
+// reported as a body mismatch.
+//
+//	httpmock.OnJSONValue(Mock, http.MethodPost, "/users", User{Name: "foo"}).RespondJSON(http.StatusCreated, user)
+func OnJSONValue[T any](m *Mock, method string, URL string, want T) *Request {
+	body, err := json.Marshal(want)
+	if err != nil {
+		m.fail("failed to marshal JSON body. Error: %v\n", err)
+	}
+	description := fmt.Sprintf("(JSON %s)", reflect.TypeFor[T]())
+
+	expected := m.On(method, URL, body)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	expected.typedBody = &typedJSONBody{
+		description: fmt.Sprintf("%s (%d) %s", description, len(body), trimBody(body)),
+		match: func(otherBody []byte) MatchResult {
+			got, result, ok := decodeTypedJSON[T](otherBody, description)
+			if !ok {
+				return result
+			}
+
+			gotBody, _ := json.Marshal(got)
+			a := fmt.Sprintf("(%d) %s", len(gotBody), trimBody(gotBody))
+			e := fmt.Sprintf("(%d) %s", len(body), trimBody(body))
+			if reflect.DeepEqual(got, want) {
+				result = newMatchResult("Body", a, e, true)
+				result.Message = description
+				return result
+			}
+
+			result = newMatchResult("Body", a, e, false)
+			result.Message = description
+			actual, aerr := decodeJSON(gotBody)
+			expected, eerr := decodeJSON(body)
+			if aerr == nil && eerr == nil {
+				for _, d := range diffJSON("$", actual, expected, false) {
+					result.Children = append(result.Children, newMatchResult(d.path, d.actual, d.expected, false))
+				}
+			}
+			return result
+		},
+	}
+	return expected
+}
+
+// OnJSONFunc starts a description of an expectation of the specified
+// [Request] with a JSON body that decodes into a value of type T for which the
+// provided predicate returns true. The received body is decoded with
+// [json.Unmarshal]. A body that cannot be decoded is reported as a body
+// mismatch.
+//
+//	httpmock.OnJSONFunc(Mock, http.MethodPost, "/users", func(u User) bool {
+//		return u.Name != ""
+//	}).RespondJSON(http.StatusCreated, user)
+func OnJSONFunc[T any](m *Mock, method string, URL string, predicate func(T) bool) *Request {
+	description := fmt.Sprintf("(JSON %s)", reflect.TypeFor[T]())
+
+	expected := m.On(method, URL, nil)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	expected.typedBody = &typedJSONBody{
+		description: fmt.Sprintf("%s %s", description, funcName(predicate)),
+		match: func(otherBody []byte) MatchResult {
+			got, result, ok := decodeTypedJSON[T](otherBody, description)
+			if !ok {
+				return result
+			}
+
+			gotBody, _ := json.Marshal(got)
+			result = newMatchResult("Body", fmt.Sprintf("(%d) %s", len(gotBody), trimBody(gotBody)), funcName(predicate), predicate(got))
+			result.Message = description
+			return result
+		},
+	}
+	return expected
+}
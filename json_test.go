@@ -0,0 +1,362 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diffJSON(t *testing.T) {
+	tests := []struct {
+		name             string
+		actual           string
+		expected         string
+		allowExtraFields bool
+		want             []jsonDifference
+	}{
+		{
+			name:     "equal-unordered",
+			actual:   `{"b": 2, "a": 1}`,
+			expected: `{"a":1,"b":2}`,
+		},
+		{
+			name:     "equal-numbers",
+			actual:   `{"a": 1.0, "b": 1e2}`,
+			expected: `{"a": 1, "b": 100}`,
+		},
+		{
+			name:     "different-value",
+			actual:   `{"items": [{"id": 1}, {"id": 2}, {"id": 5}]}`,
+			expected: `{"items": [{"id": 1}, {"id": 2}, {"id": 7}]}`,
+			want:     []jsonDifference{{"$.items[2].id", "5", "7"}},
+		},
+		{
+			name:     "different-types",
+			actual:   `{"a": "1"}`,
+			expected: `{"a": 1}`,
+			want:     []jsonDifference{{"$.a", `"1"`, "1"}},
+		},
+		{
+			name:     "missing-field",
+			actual:   `{"a": 1}`,
+			expected: `{"a": 1, "b": {"c": true}}`,
+			want:     []jsonDifference{{"$.b", fmtMissing, `{"c":true}`}},
+		},
+		{
+			name:     "extra-field",
+			actual:   `{"a": 1, "first-name": null}`,
+			expected: `{"a": 1}`,
+			want:     []jsonDifference{{`$["first-name"]`, "null", fmtMissing}},
+		},
+		{
+			name:             "extra-field-allowed",
+			actual:           `{"a": 1, "b": {"c": 2, "d": 3}}`,
+			expected:         `{"b": {"d": 3}}`,
+			allowExtraFields: true,
+		},
+		{
+			name:     "missing-element",
+			actual:   `[1]`,
+			expected: `[1, 2]`,
+			want:     []jsonDifference{{"$[1]", fmtMissing, "2"}},
+		},
+		{
+			name:             "extra-element",
+			actual:           `[1, 2]`,
+			expected:         `[1]`,
+			allowExtraFields: true,
+			want:             []jsonDifference{{"$[1]", "2", fmtMissing}},
+		},
+		{
+			name:     "different-root",
+			actual:   `[1]`,
+			expected: `{"a": 1}`,
+			want:     []jsonDifference{{"$", "[1]", `{"a":1}`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			actual, err := decodeJSON([]byte(tt.actual))
+			if err != nil {
+				t.Fatalf("unexpected error parsing actual: %v", err)
+			}
+			expected, err := decodeJSON([]byte(tt.expected))
+			if err != nil {
+				t.Fatalf("unexpected error parsing expected: %v", err)
+			}
+
+			// Test
+			got := diffJSON("$", actual, expected, tt.allowExtraFields)
+
+			// Assertions
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRequest_diffJSONBody(t *testing.T) {
+	tests := []struct {
+		name            string
+		request         *Request
+		received        string
+		wantOutput      string
+		wantDifferences int
+	}{
+		{
+			name:            "equal",
+			request:         &Request{body: []byte(`{"a":1,"b":2}`), jsonBody: true},
+			received:        `{"b": 2, "a": 1}`,
+			wantOutput:      "\t2: PASS:  (JSON) (16) {\"b\": 2, \"a\": 1} == (13) {\"a\":1,\"b\":2}\n",
+			wantDifferences: 0,
+		},
+		{
+			name:            "different",
+			request:         &Request{body: []byte(`{"a":1,"b":2}`), jsonBody: true},
+			received:        `{"a": 3}`,
+			wantOutput:      "\t2: FAIL:  (JSON)\n\t\t  $.a:  3 != 1\n\t\t  $.b:  (Missing) != 2\n",
+			wantDifferences: 1,
+		},
+		{
+			name:            "invalid-received",
+			request:         &Request{body: []byte(`{"a":1}`), jsonBody: true},
+			received:        `{"a":`,
+			wantOutput:      "\t2: FAIL:  (JSON) (5) {\"a\": unable to be parsed: unexpected EOF\n",
+			wantDifferences: 1,
+		},
+		{
+			name:            "empty-received",
+			request:         &Request{body: []byte(`{"a":1}`), jsonBody: true},
+			received:        ``,
+			wantOutput:      "\t2: FAIL:  (JSON) (0) (Missing) unable to be parsed: EOF\n",
+			wantDifferences: 1,
+		},
+		{
+			name:            "trailing-data",
+			request:         &Request{body: []byte(`{"a":1}`), jsonBody: true},
+			received:        `{"a":1} {}`,
+			wantOutput:      "\t2: FAIL:  (JSON) (10) {\"a\":1} {} unable to be parsed: unexpected data after top-level value\n",
+			wantDifferences: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			received := &http.Request{Body: io.NopCloser(strings.NewReader(tt.received))}
+
+			// Test
+			gotOutput, gotDifferences := tt.request.diffBody(received)
+
+			// Assertions
+			assert.Equal(t, tt.wantOutput, gotOutput)
+			assert.Equal(t, tt.wantDifferences, gotDifferences)
+		})
+	}
+}
+
+func TestRequest_diffJSONBody_FailToReadBody(t *testing.T) {
+	// Setup
+	r := &Request{body: []byte(`{"a":1}`), jsonBody: true}
+
+	received := &http.Request{Body: io.NopCloser(&badReader{})}
+
+	// Test
+	gotOutput, gotDifferences := r.diffBody(received)
+
+	// Assertions
+	assert.Contains(t, gotOutput, ErrReadBody.Error())
+	assert.NotContains(t, gotOutput, "(JSON)")
+	assert.Equal(t, 1, gotDifferences)
+}
+
+func TestRequest_matchJSONBody(t *testing.T) {
+	tests := []struct {
+		name            string
+		request         *Request
+		received        string
+		wantOutput      string
+		wantDifferences int
+	}{
+		{
+			name:            "equal",
+			request:         &Request{body: []byte(`{"a":1,"b":2}`), jsonBody: true},
+			received:        `{"b": 2, "a": 1}`,
+			wantOutput:      "PASS:  Body: (JSON) (16) {\"b\": 2, \"a\": 1} == (13) {\"a\":1,\"b\":2}",
+			wantDifferences: 0,
+		},
+		{
+			name:            "different",
+			request:         &Request{body: []byte(`{"a":1,"b":2}`), jsonBody: true},
+			received:        `{"a": 3}`,
+			wantOutput:      "FAIL:  Body: (JSON)\n\t0: FAIL:  $.a: 3 != 1\n\t1: FAIL:  $.b: (Missing) != 2",
+			wantDifferences: 1,
+		},
+		{
+			name:            "invalid-received",
+			request:         &Request{body: []byte(`{"a":1}`), jsonBody: true},
+			received:        `{"a":`,
+			wantOutput:      "FAIL:  Body: (JSON) (5) {\"a\": unable to be parsed: unexpected EOF",
+			wantDifferences: 1,
+		},
+		{
+			name:            "empty-received",
+			request:         &Request{body: []byte(`{"a":1}`), jsonBody: true},
+			received:        ``,
+			wantOutput:      "FAIL:  Body: (JSON) (0) (Missing) unable to be parsed: EOF",
+			wantDifferences: 1,
+		},
+		{
+			name:            "trailing-data",
+			request:         &Request{body: []byte(`{"a":1}`), jsonBody: true},
+			received:        `{"a":1} {}`,
+			wantOutput:      "FAIL:  Body: (JSON) (10) {\"a\":1} {} unable to be parsed: unexpected data after top-level value",
+			wantDifferences: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			received := &http.Request{Body: io.NopCloser(strings.NewReader(tt.received))}
+
+			// Test
+			got := tt.request.matchBody(received)
+
+			// Assertions
+			assert.Equal(t, tt.wantOutput, got.String())
+			assert.Equal(t, tt.wantDifferences, got.Differences)
+		})
+	}
+}
+
+type testJSONUser struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+func TestOnJSONValue(t *testing.T) {
+	tests := []struct {
+		name            string
+		received        string
+		wantOutput      string
+		wantDifferences int
+	}{
+		{
+			name:            "equal",
+			received:        `{"name": "foo", "id": "1234"}`,
+			wantOutput:      "PASS:  Body: (JSON httpmock.testJSONUser) (26) {\"id\":\"1234\",\"name\":\"foo\"} == (26) {\"id\":\"1234\",\"name\":\"foo\"}",
+			wantDifferences: 0,
+		},
+		{
+			name:            "unknown-fields",
+			received:        `{"name": "foo", "id": "1234", "email": "foo@test.com"}`,
+			wantOutput:      "PASS:  Body: (JSON httpmock.testJSONUser) (26) {\"id\":\"1234\",\"name\":\"foo\"} == (26) {\"id\":\"1234\",\"name\":\"foo\"}",
+			wantDifferences: 0,
+		},
+		{
+			name:            "different",
+			received:        `{"id": "5678", "name": "foo", "roles": ["admin"]}`,
+			wantOutput:      "FAIL:  Body: (JSON httpmock.testJSONUser) (44) {\"id\":\"5678\",\"name\":\"foo\",\"roles\":[\"admin\"]} != (26) {\"id\":\"1234\",\"name\":\"foo\"}\n\t0: FAIL:  $.id: \"5678\" != \"1234\"\n\t1: FAIL:  $.roles: [\"admin\"] != (Missing)",
+			wantDifferences: 1,
+		},
+		{
+			name:            "undecodable",
+			received:        `{"id": 1234}`,
+			wantOutput:      "FAIL:  Body: (JSON httpmock.testJSONUser) (12) {\"id\": 1234} unable to be decoded: json: cannot unmarshal number into Go struct field testJSONUser.id of type string",
+			wantDifferences: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock)
+			r := OnJSONValue(m, http.MethodPost, "/users", testJSONUser{ID: "1234", Name: "foo"})
+			received := &http.Request{Body: io.NopCloser(strings.NewReader(tt.received))}
+
+			// Test
+			got := r.matchBody(received)
+
+			// Assertions
+			assert.Equal(t, tt.wantOutput, got.String())
+			assert.Equal(t, tt.wantDifferences, got.Differences)
+			assert.Contains(t, r.String(), "Body: (JSON httpmock.testJSONUser) (26) {\"id\":\"1234\",\"name\":\"foo\"}")
+		})
+	}
+}
+
+func TestOnJSONFunc(t *testing.T) {
+	hasAdmin := func(u testJSONUser) bool {
+		return slices.Contains(u.Roles, "admin")
+	}
+
+	tests := []struct {
+		name            string
+		received        string
+		wantOutput      string
+		wantDifferences int
+	}{
+		{
+			name:            "pass",
+			received:        `{"id": "1234", "roles": ["admin"]}`,
+			wantOutput:      "PASS:  Body: (JSON httpmock.testJSONUser) (41) {\"id\":\"1234\",\"name\":\"\",\"roles\":[\"admin\"]} == " + funcName(hasAdmin),
+			wantDifferences: 0,
+		},
+		{
+			name:            "fail",
+			received:        `{"id": "1234"}`,
+			wantOutput:      "FAIL:  Body: (JSON httpmock.testJSONUser) (23) {\"id\":\"1234\",\"name\":\"\"} != " + funcName(hasAdmin),
+			wantDifferences: 1,
+		},
+		{
+			name:            "undecodable",
+			received:        `[]`,
+			wantOutput:      "FAIL:  Body: (JSON httpmock.testJSONUser) (2) [] unable to be decoded: json: cannot unmarshal array into Go value of type httpmock.testJSONUser",
+			wantDifferences: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock)
+			r := OnJSONFunc(m, http.MethodPost, "/users", hasAdmin)
+			received := &http.Request{Body: io.NopCloser(strings.NewReader(tt.received))}
+
+			// Test
+			got := r.matchBody(received)
+
+			// Assertions
+			assert.Equal(t, tt.wantOutput, got.String())
+			assert.Equal(t, tt.wantDifferences, got.Differences)
+			assert.Contains(t, r.String(), "Body: (JSON httpmock.testJSONUser) "+funcName(hasAdmin))
+		})
+	}
+}
+
+func TestOnJSONValue_Requested(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	OnJSONValue(m, http.MethodPost, "https://test.com/users", testJSONUser{ID: "1234", Name: "foo"}).
+		RespondJSON(http.StatusCreated, testJSONUser{ID: "1234", Name: "foo"})
+
+	// Test
+	got, err := m.requested(mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/users", strings.NewReader(`{"id": "1234", "name": "foo"}`))))
+	_, gotErr := m.requested(mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/users", strings.NewReader(`{"id": 1234}`))))
+
+	// Assertions
+	assert.NoError(t, err)
+	if assert.NotNil(t, got) {
+		assert.Equal(t, http.StatusCreated, got.StatusCode())
+		assert.Equal(t, []byte(`{"id":"1234","name":"foo"}`), got.Body())
+	}
+	assert.ErrorIs(t, gotErr, ErrUnexpectedRequest)
+	assert.ErrorContains(t, gotErr, "unable to be decoded")
+}
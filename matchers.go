@@ -0,0 +1,254 @@
+package httpmock
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// headerValues returns the values of a received header, quoted and joined
+// for output, and whether or not the header was present.
+func headerValues(received *http.Request, key string) ([]string, string, bool) {
+	values := received.Header.Values(key)
+	if len(values) == 0 {
+		return nil, fmtMissing, false
+	}
+
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return values, strings.Join(quoted, ", "), true
+}
+
+// HeaderEquals creates a [ResultMatcher] that expects any value of the
+// header to be equal to the provided value.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(HeaderEquals("Accept", "application/json"))
+func HeaderEquals(key string, value string) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		values, actual, _ := headerValues(received, key)
+
+		var pass bool
+		for _, v := range values {
+			if v == value {
+				pass = true
+				break
+			}
+		}
+		return newMatchResult("header "+key, actual, fmt.Sprintf("%q", value), pass)
+	}
+}
+
+// HeaderContains creates a [ResultMatcher] that expects any value of the
+// header to contain the provided substring.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(HeaderContains("Accept", "json"))
+func HeaderContains(key string, substr string) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		values, actual, _ := headerValues(received, key)
+
+		var pass bool
+		for _, v := range values {
+			if strings.Contains(v, substr) {
+				pass = true
+				break
+			}
+		}
+		return newMatchResult("header "+key, actual, fmt.Sprintf("(Contains) %q", substr), pass)
+	}
+}
+
+// HeaderRegexp creates a [ResultMatcher] that expects any value of the
+// header to match the provided regular expression. It panics if the
+// expression cannot be compiled.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(HeaderRegexp("X-Request-Id", `^[0-9a-f-]{36}$`))
+func HeaderRegexp(key string, expr string) ResultMatcher {
+	re := regexp.MustCompile(expr)
+
+	return func(received *http.Request) MatchResult {
+		values, actual, _ := headerValues(received, key)
+
+		var pass bool
+		for _, v := range values {
+			if re.MatchString(v) {
+				pass = true
+				break
+			}
+		}
+		return newMatchResult("header "+key, actual, fmt.Sprintf("(Regexp) %s", re), pass)
+	}
+}
+
+// HeaderPresent creates a [ResultMatcher] that expects the header to be
+// present, with any value.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(HeaderPresent("X-Request-Id"))
+func HeaderPresent(key string) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		_, actual, ok := headerValues(received, key)
+		if ok {
+			actual = fmtPresent
+		}
+		return newMatchResult("header "+key, actual, fmtPresent, ok)
+	}
+}
+
+// HeaderAbsent creates a [ResultMatcher] that expects the header to not be
+// present.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(HeaderAbsent("Authorization"))
+func HeaderAbsent(key string) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		_, actual, ok := headerValues(received, key)
+		return newMatchResult("header "+key, actual, fmtMissing, !ok)
+	}
+}
+
+// BearerToken creates a [ResultMatcher] that expects the Authorization header
+// to contain a bearer token equal to the provided token. The authorization
+// scheme is matched case-insensitively.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(BearerToken("jkel3450d"))
+func BearerToken(token string) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		actual := received.Header.Get("Authorization")
+		if actual == "" {
+			return newMatchResult("bearer token", fmtMissing, fmt.Sprintf("%q", token), false)
+		}
+
+		scheme, credentials, ok := strings.Cut(actual, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return newMatchResult("bearer token", fmt.Sprintf("(%s)", scheme), "(Bearer)", false)
+		}
+		return newMatchResult("bearer token", fmt.Sprintf("%q", credentials), fmt.Sprintf("%q", token), credentials == token)
+	}
+}
+
+// BasicAuth creates a [ResultMatcher] that expects the Authorization header
+// to contain basic authentication credentials equal to the provided username
+// and password.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(BasicAuth("alice", "secret"))
+func BasicAuth(username string, password string) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		expected := fmt.Sprintf("%q:%q", username, password)
+
+		u, p, ok := received.BasicAuth()
+		if !ok {
+			return newMatchResult("basic auth", fmtMissing, expected, false)
+		}
+		return newMatchResult("basic auth", fmt.Sprintf("%q:%q", u, p), expected, u == username && p == password)
+	}
+}
+
+// ContentType creates a [ResultMatcher] that expects the Content-Type header
+// to have the provided media type. Media types are compared case-insensitively
+// and any parameters, such as charset, are ignored.
+//
+//	Mock.On(http.MethodPost, "/some/path", AnyBody).Matches(ContentType("application/json"))
+func ContentType(mediaType string) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		actual := received.Header.Get("Content-Type")
+		if actual == "" {
+			return newMatchResult("content type", fmtMissing, mediaType, false)
+		}
+
+		parsed, _, err := mime.ParseMediaType(actual)
+		if err != nil {
+			return MatchResult{Field: "content type", Message: fmt.Sprintf("%q unable to be parsed: %v", actual, err), Differences: 1}
+		}
+		return newMatchResult("content type", parsed, mediaType, strings.EqualFold(parsed, mediaType))
+	}
+}
+
+// Cookie creates a [ResultMatcher] that expects the request to contain a
+// cookie with the provided name and value.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(Cookie("session", "1234"))
+func Cookie(name string, value string) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		c, err := received.Cookie(name)
+		if err != nil {
+			return newMatchResult("cookie "+name, fmtMissing, fmt.Sprintf("%q", value), false)
+		}
+		return newMatchResult("cookie "+name, fmt.Sprintf("%q", c.Value), fmt.Sprintf("%q", value), c.Value == value)
+	}
+}
+
+// AllOf creates a [ResultMatcher] that expects all of the provided
+// [ResultMatcher]'s to match. The differences of each failing
+// [ResultMatcher] are added together, and the result of each is included as a
+// child. Use [AdaptMatcher] to combine a [RequestMatcher].
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(AllOf(HeaderPresent("X-Request-Id"), BearerToken("jkel3450d")))
+func AllOf(matchers ...ResultMatcher) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		result := MatchResult{Field: "AllOf"}
+		var passed int
+		for _, fn := range matchers {
+			child := fn(received)
+			if child.Pass {
+				passed++
+			}
+			result.Differences += child.differences()
+			result.Children = append(result.Children, child)
+		}
+
+		result.Pass = result.Differences == 0
+		result.Message = fmt.Sprintf("%d of %d passed", passed, len(matchers))
+		return result
+	}
+}
+
+// AnyOf creates a [ResultMatcher] that expects at least one of the provided
+// [ResultMatcher]'s to match. If none match, the differences of the closest
+// [ResultMatcher] are used. The result of each is included as a child.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(AnyOf(HeaderPresent("X-Api-Key"), BearerToken("jkel3450d")))
+func AnyOf(matchers ...ResultMatcher) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		result := MatchResult{Field: "AnyOf"}
+		var passed int
+		closest := -1
+		for _, fn := range matchers {
+			child := fn(received)
+			if d := child.differences(); d == 0 {
+				passed++
+			} else if closest < 0 || d < closest {
+				closest = d
+			}
+			result.Children = append(result.Children, child)
+		}
+
+		result.Pass = passed != 0
+		if !result.Pass {
+			result.Differences = max(closest, 1)
+		}
+		result.Message = fmt.Sprintf("%d of %d passed", passed, len(matchers))
+		return result
+	}
+}
+
+// Not creates a [ResultMatcher] that expects the provided [ResultMatcher] to
+// not match. Its result is included as a child.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).Matches(Not(HeaderPresent("Authorization")))
+func Not(matcher ResultMatcher) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		child := matcher(received)
+
+		result := MatchResult{Field: "Not", Children: []MatchResult{child}}
+		if child.differences() == 0 {
+			result.Message = "matcher passed"
+			result.Differences = 1
+		} else {
+			result.Message = "matcher failed"
+			result.Pass = true
+		}
+		return result
+	}
+}
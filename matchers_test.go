@@ -0,0 +1,330 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchers(t *testing.T) {
+	newReceived := func(header http.Header) *http.Request {
+		received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody))
+		received.Header = header
+		return received
+	}
+
+	tests := []struct {
+		name            string
+		matcher         ResultMatcher
+		received        *http.Request
+		wantOutput      string
+		wantDifferences int
+	}{
+		{
+			name:            "header-equals",
+			matcher:         HeaderEquals("Accept", "application/json"),
+			received:        newReceived(http.Header{"Accept": []string{"text/html", "application/json"}}),
+			wantOutput:      `PASS:  header Accept: "text/html", "application/json" == "application/json"`,
+			wantDifferences: 0,
+		},
+		{
+			name:            "header-equals-mismatch",
+			matcher:         HeaderEquals("Accept", "application/json"),
+			received:        newReceived(http.Header{"Accept": []string{"text/html"}}),
+			wantOutput:      `FAIL:  header Accept: "text/html" != "application/json"`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "header-equals-missing",
+			matcher:         HeaderEquals("Accept", "application/json"),
+			received:        newReceived(http.Header{}),
+			wantOutput:      `FAIL:  header Accept: (Missing) != "application/json"`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "header-contains",
+			matcher:         HeaderContains("Accept", "json"),
+			received:        newReceived(http.Header{"Accept": []string{"application/json"}}),
+			wantOutput:      `PASS:  header Accept: "application/json" == (Contains) "json"`,
+			wantDifferences: 0,
+		},
+		{
+			name:            "header-contains-mismatch",
+			matcher:         HeaderContains("Accept", "json"),
+			received:        newReceived(http.Header{"Accept": []string{"text/html"}}),
+			wantOutput:      `FAIL:  header Accept: "text/html" != (Contains) "json"`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "header-regexp",
+			matcher:         HeaderRegexp("X-Request-Id", `^[0-9]+$`),
+			received:        newReceived(http.Header{"X-Request-Id": []string{"1234"}}),
+			wantOutput:      `PASS:  header X-Request-Id: "1234" == (Regexp) ^[0-9]+$`,
+			wantDifferences: 0,
+		},
+		{
+			name:            "header-regexp-mismatch",
+			matcher:         HeaderRegexp("X-Request-Id", `^[0-9]+$`),
+			received:        newReceived(http.Header{"X-Request-Id": []string{"abcd"}}),
+			wantOutput:      `FAIL:  header X-Request-Id: "abcd" != (Regexp) ^[0-9]+$`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "header-present",
+			matcher:         HeaderPresent("X-Request-Id"),
+			received:        newReceived(http.Header{"X-Request-Id": []string{"1234"}}),
+			wantOutput:      `PASS:  header X-Request-Id: (Present) == (Present)`,
+			wantDifferences: 0,
+		},
+		{
+			name:            "header-present-missing",
+			matcher:         HeaderPresent("X-Request-Id"),
+			received:        newReceived(http.Header{}),
+			wantOutput:      `FAIL:  header X-Request-Id: (Missing) != (Present)`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "header-absent",
+			matcher:         HeaderAbsent("Authorization"),
+			received:        newReceived(http.Header{}),
+			wantOutput:      `PASS:  header Authorization: (Missing) == (Missing)`,
+			wantDifferences: 0,
+		},
+		{
+			name:            "header-absent-present",
+			matcher:         HeaderAbsent("Authorization"),
+			received:        newReceived(http.Header{"Authorization": []string{"Bearer 1234"}}),
+			wantOutput:      `FAIL:  header Authorization: "Bearer 1234" != (Missing)`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "bearer-token",
+			matcher:         BearerToken("jkel3450d"),
+			received:        newReceived(http.Header{"Authorization": []string{"bearer jkel3450d"}}),
+			wantOutput:      `PASS:  bearer token: "jkel3450d" == "jkel3450d"`,
+			wantDifferences: 0,
+		},
+		{
+			name:            "bearer-token-mismatch",
+			matcher:         BearerToken("jkel3450d"),
+			received:        newReceived(http.Header{"Authorization": []string{"Bearer abcd"}}),
+			wantOutput:      `FAIL:  bearer token: "abcd" != "jkel3450d"`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "bearer-token-wrong-scheme",
+			matcher:         BearerToken("jkel3450d"),
+			received:        newReceived(http.Header{"Authorization": []string{"Basic YWxpY2U6c2VjcmV0"}}),
+			wantOutput:      `FAIL:  bearer token: (Basic) != (Bearer)`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "bearer-token-missing",
+			matcher:         BearerToken("jkel3450d"),
+			received:        newReceived(http.Header{}),
+			wantOutput:      `FAIL:  bearer token: (Missing) != "jkel3450d"`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "basic-auth",
+			matcher:         BasicAuth("alice", "secret"),
+			received:        newReceived(http.Header{"Authorization": []string{"Basic YWxpY2U6c2VjcmV0"}}),
+			wantOutput:      `PASS:  basic auth: "alice":"secret" == "alice":"secret"`,
+			wantDifferences: 0,
+		},
+		{
+			name:            "basic-auth-mismatch",
+			matcher:         BasicAuth("alice", "password"),
+			received:        newReceived(http.Header{"Authorization": []string{"Basic YWxpY2U6c2VjcmV0"}}),
+			wantOutput:      `FAIL:  basic auth: "alice":"secret" != "alice":"password"`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "basic-auth-missing",
+			matcher:         BasicAuth("alice", "secret"),
+			received:        newReceived(http.Header{}),
+			wantOutput:      `FAIL:  basic auth: (Missing) != "alice":"secret"`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "content-type",
+			matcher:         ContentType("application/json"),
+			received:        newReceived(http.Header{"Content-Type": []string{"Application/JSON; charset=utf-8"}}),
+			wantOutput:      `PASS:  content type: application/json == application/json`,
+			wantDifferences: 0,
+		},
+		{
+			name:            "content-type-mismatch",
+			matcher:         ContentType("application/json"),
+			received:        newReceived(http.Header{"Content-Type": []string{"text/plain"}}),
+			wantOutput:      `FAIL:  content type: text/plain != application/json`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "content-type-missing",
+			matcher:         ContentType("application/json"),
+			received:        newReceived(http.Header{}),
+			wantOutput:      `FAIL:  content type: (Missing) != application/json`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "content-type-invalid",
+			matcher:         ContentType("application/json"),
+			received:        newReceived(http.Header{"Content-Type": []string{"application/json; charset"}}),
+			wantOutput:      `FAIL:  content type: "application/json; charset" unable to be parsed: mime: invalid media parameter`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "cookie",
+			matcher:         Cookie("session", "1234"),
+			received:        newReceived(http.Header{"Cookie": []string{"theme=dark; session=1234"}}),
+			wantOutput:      `PASS:  cookie session: "1234" == "1234"`,
+			wantDifferences: 0,
+		},
+		{
+			name:            "cookie-mismatch",
+			matcher:         Cookie("session", "1234"),
+			received:        newReceived(http.Header{"Cookie": []string{"session=5678"}}),
+			wantOutput:      `FAIL:  cookie session: "5678" != "1234"`,
+			wantDifferences: 1,
+		},
+		{
+			name:            "cookie-missing",
+			matcher:         Cookie("session", "1234"),
+			received:        newReceived(http.Header{}),
+			wantOutput:      `FAIL:  cookie session: (Missing) != "1234"`,
+			wantDifferences: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got := tt.matcher(tt.received)
+
+			// Assertions
+			gotOutput, gotDifferences := got.output()
+			assert.Equal(t, tt.wantOutput, gotOutput)
+			assert.Equal(t, tt.wantDifferences, gotDifferences)
+			assert.Equal(t, tt.wantDifferences == 0, got.Pass)
+			assert.NotEmpty(t, got.Field)
+		})
+	}
+}
+
+func TestMatchers_Fields(t *testing.T) {
+	// Setup
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody))
+	received.Header.Set("Accept", "text/html")
+
+	// Test
+	got := HeaderEquals("Accept", "application/json")(received)
+
+	// Assertions
+	assert.Equal(t, newMatchResult("header Accept", `"text/html"`, `"application/json"`, false), got)
+}
+
+func TestMatchers_Requested(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	m := new(Mock).Test(mockT)
+	m.On(http.MethodGet, "https://test.com/foo", nil).
+		Matches(BearerToken("jkel3450d"), ContentType("application/json"), testRequestMatcherAlwaysPass).
+		RespondOK(nil)
+
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody))
+	received.Header.Set("Authorization", "Bearer jkel3450d")
+	received.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	// Test
+	got := m.Requested(received)
+
+	// Assertions
+	assert.NotNil(t, got)
+	assert.Zero(t, mockT.failNowCount)
+}
+
+func TestMatchers_Combinators(t *testing.T) {
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo?page=2", http.NoBody))
+	received.Header.Set("Authorization", "Bearer jkel3450d")
+
+	tests := []struct {
+		name            string
+		matcher         ResultMatcher
+		wantOutput      string
+		wantString      string
+		wantDifferences int
+	}{
+		{
+			name:            "all-of",
+			matcher:         AllOf(HeaderPresent("Authorization"), BearerToken("jkel3450d")),
+			wantOutput:      "PASS:  AllOf: 2 of 2 passed\n\t\t0: PASS:  header Authorization: (Present) == (Present)\n\t\t1: PASS:  bearer token: \"jkel3450d\" == \"jkel3450d\"",
+			wantString:      "PASS:  AllOf: 2 of 2 passed\n\t0: PASS:  header Authorization: (Present) == (Present)\n\t1: PASS:  bearer token: \"jkel3450d\" == \"jkel3450d\"",
+			wantDifferences: 0,
+		},
+		{
+			name:            "all-of-mismatch",
+			matcher:         AllOf(HeaderPresent("Authorization"), BearerToken("abcd"), HeaderPresent("X-Request-Id")),
+			wantOutput:      "FAIL:  AllOf: 1 of 3 passed\n\t\t0: PASS:  header Authorization: (Present) == (Present)\n\t\t1: FAIL:  bearer token: \"jkel3450d\" != \"abcd\"\n\t\t2: FAIL:  header X-Request-Id: (Missing) != (Present)",
+			wantString:      "FAIL:  AllOf: 1 of 3 passed\n\t0: PASS:  header Authorization: (Present) == (Present)\n\t1: FAIL:  bearer token: \"jkel3450d\" != \"abcd\"\n\t2: FAIL:  header X-Request-Id: (Missing) != (Present)",
+			wantDifferences: 2,
+		},
+		{
+			name:            "all-of-adapted",
+			matcher:         AllOf(AdaptMatcher(testRequestMatcherAlwaysPass), BearerToken("jkel3450d")),
+			wantOutput:      "PASS:  AllOf: 2 of 2 passed\n\t\t0: PASS:  GOOD == GOOD\n\t\t1: PASS:  bearer token: \"jkel3450d\" == \"jkel3450d\"",
+			wantString:      "PASS:  AllOf: 2 of 2 passed\n\t0: PASS:  GOOD == GOOD\n\t1: PASS:  bearer token: \"jkel3450d\" == \"jkel3450d\"",
+			wantDifferences: 0,
+		},
+		{
+			name:            "any-of",
+			matcher:         AnyOf(HeaderPresent("X-Api-Key"), BearerToken("jkel3450d")),
+			wantOutput:      "PASS:  AnyOf: 1 of 2 passed\n\t\t0: FAIL:  header X-Api-Key: (Missing) != (Present)\n\t\t1: PASS:  bearer token: \"jkel3450d\" == \"jkel3450d\"",
+			wantString:      "PASS:  AnyOf: 1 of 2 passed\n\t0: FAIL:  header X-Api-Key: (Missing) != (Present)\n\t1: PASS:  bearer token: \"jkel3450d\" == \"jkel3450d\"",
+			wantDifferences: 0,
+		},
+		{
+			name:            "any-of-mismatch",
+			matcher:         AnyOf(HeaderPresent("X-Api-Key"), AllOf(BearerToken("abcd"), HeaderPresent("X-Request-Id"))),
+			wantOutput:      "FAIL:  AnyOf: 0 of 2 passed\n\t\t0: FAIL:  header X-Api-Key: (Missing) != (Present)\n\t\t1: FAIL:  AllOf: 0 of 2 passed\n\t\t\t0: FAIL:  bearer token: \"jkel3450d\" != \"abcd\"\n\t\t\t1: FAIL:  header X-Request-Id: (Missing) != (Present)",
+			wantString:      "FAIL:  AnyOf: 0 of 2 passed\n\t0: FAIL:  header X-Api-Key: (Missing) != (Present)\n\t1: FAIL:  AllOf: 0 of 2 passed\n\t\t0: FAIL:  bearer token: \"jkel3450d\" != \"abcd\"\n\t\t1: FAIL:  header X-Request-Id: (Missing) != (Present)",
+			wantDifferences: 1,
+		},
+		{
+			name:            "any-of-empty",
+			matcher:         AnyOf(),
+			wantOutput:      "FAIL:  AnyOf: 0 of 0 passed",
+			wantString:      "FAIL:  AnyOf: 0 of 0 passed",
+			wantDifferences: 1,
+		},
+		{
+			name:            "not",
+			matcher:         Not(HeaderPresent("X-Api-Key")),
+			wantOutput:      "PASS:  Not: matcher failed\n\t\t0: FAIL:  header X-Api-Key: (Missing) != (Present)",
+			wantString:      "PASS:  Not: matcher failed\n\t0: FAIL:  header X-Api-Key: (Missing) != (Present)",
+			wantDifferences: 0,
+		},
+		{
+			name:            "not-mismatch",
+			matcher:         Not(BearerToken("jkel3450d")),
+			wantOutput:      "FAIL:  Not: matcher passed\n\t\t0: PASS:  bearer token: \"jkel3450d\" == \"jkel3450d\"",
+			wantString:      "FAIL:  Not: matcher passed\n\t0: PASS:  bearer token: \"jkel3450d\" == \"jkel3450d\"",
+			wantDifferences: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got := tt.matcher(received)
+
+			// Assertions
+			gotOutput, gotDifferences := got.output()
+			assert.Equal(t, tt.wantOutput, gotOutput)
+			assert.Equal(t, tt.wantString, got.String())
+			assert.Equal(t, tt.wantDifferences, gotDifferences)
+			assert.Equal(t, tt.wantDifferences == 0, got.Pass)
+		})
+	}
+}
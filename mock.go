@@ -2,17 +2,52 @@ package httpmock
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+var (
+	ErrUnexpectedRequest = errors.New("unexpected request")
+	ErrOutOfOrder        = errors.New("request out of order")
+)
+
+// requestError is an error that occurred while handling a received request.
+// Its message is formatted for test output, and it wraps a sentinel error so
+// that it may be inspected with [errors.Is].
+type requestError struct {
+	err error
+
+	message string
+}
+
+// newRequestError creates a [requestError] that wraps a sentinel error.
+func newRequestError(err error, format string, args ...any) error {
+	return &requestError{err: err, message: fmt.Sprintf(format, args...)}
+}
+
+// Error returns the formatted message of the error.
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the sentinel error.
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
 // tHelper is a minimal interface that expects a type to satisfy the
 // [testing.TB] Helper method.
 type tHelper interface {
@@ -32,6 +67,46 @@ type Mock struct {
 	// an invalid mock request was made.
 	test mock.TestingT
 
+	// Source of randomness for random response delays. It is created on first
+	// use, unless it was seeded with [Mock.Seed].
+	rand *rand.Rand
+
+	// Errors that occurred while handling requests on a goroutine other than
+	// the test's, such as in a [Server] handler. They are reported on the
+	// test's goroutine by [Mock.AssertExpectations] and
+	// [Mock.AssertNoFailures].
+	failures []error
+
+	// Number of failures that have already been reported.
+	reportedFailures int
+
+	// Whether or not expectations must be requested in the order in which they
+	// were registered.
+	strictOrder bool
+
+	// Closed and replaced whenever a request is received or expectations are
+	// removed, to wake goroutines that are waiting for requests.
+	changed chan struct{}
+
+	// Expectation whose response is returned for requests that do not match
+	// any other expectation.
+	fallback *Request
+
+	// Whether or not the fallback expectation is kept when expectations are
+	// reset, because it proxies requests to a [Server]'s upstream.
+	keepFallback bool
+
+	// Whether or not requests matched by the fallback expectation are reported
+	// as warnings rather than failures by [Mock.AssertExpectations].
+	tolerateUnmatched bool
+
+	// Current state of each scenario that has moved to a new state.
+	scenarios map[string]string
+
+	// Number of requests that have been recorded in Requests, including any
+	// that have since been cleared. Used to number each recorded request.
+	received int
+
 	mutex sync.Mutex
 }
 
@@ -39,10 +114,19 @@ type Mock struct {
 // received.
 //
 //	Mock.On(http.MethodDelete, "/some/path/1234")
+//
+// The path may be a template using the same syntax as the path of a
+// [http.ServeMux] pattern. Values captured by the template are available to
+// [RequestMatcher]'s and [ResponseWriter]'s via [http.Request.PathValue].
+//
+//	Mock.On(http.MethodGet, "/users/{id}")
+//	Mock.On(http.MethodGet, "/files/{path...}")
 func (m *Mock) On(method string, URL string, body []byte) *Request {
 	parsedURL, err := url.Parse(URL)
 	if err != nil {
 		m.fail("failed to parse url. Error: %v\n", err)
+		// The expectation is not registered, so that it cannot be matched
+		return newRequest(m, method, new(url.URL), body)
 	}
 
 	expected := newRequest(
@@ -52,6 +136,63 @@ func (m *Mock) On(method string, URL string, body []byte) *Request {
 		body,
 	)
 
+	if isPathTemplate(parsedURL.Path) {
+		pt, err := parsePathTemplate(parsedURL.Path)
+		if err != nil {
+			m.fail("failed to parse path template. Error: %v\n", err)
+			return expected
+		}
+		expected.pathPattern = pt
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.ExpectedRequests = append(m.ExpectedRequests, expected)
+	return expected
+}
+
+// OnJSON starts a description of an expectation of the specified [Request]
+// being received with a JSON body. Bodies are compared semantically, so object
+// key order and whitespace are ignored. Use [Request.AllowExtraFields] to
+// ignore object fields in the received body that are not in the expected body.
+//
+//	Mock.OnJSON(http.MethodPost, "/some/path", []byte(`{"name": "foo", "tags": ["a", "b"]}`))
+func (m *Mock) OnJSON(method string, URL string, body []byte) *Request {
+	if _, err := decodeJSON(body); err != nil {
+		m.fail("failed to parse JSON body. Error: %v\n", err)
+	}
+
+	expected := m.On(method, URL, body)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	expected.jsonBody = true
+	return expected
+}
+
+// OnPattern starts a description of an expectation of a [Request] being
+// received with a path that matches the provided regular expression. The
+// expression must match the entire path. Named capture groups are available
+// via [http.Request.PathValue].
+//
+//	Mock.OnPattern(http.MethodGet, `/v[0-9]+/health`, nil)
+//	Mock.OnPattern(http.MethodGet, `/users/(?P<id>[0-9]+)`, nil).HostGlob("*.internal")
+func (m *Mock) OnPattern(method string, pattern string, body []byte) *Request {
+	rp, err := newRegexpPattern(pattern)
+	if err != nil {
+		m.fail("failed to parse path regexp. Error: %v\n", err)
+	}
+
+	expected := newRequest(
+		m,
+		method,
+		&url.URL{Path: pattern},
+		body,
+	)
+	expected.pathPattern = rp
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -59,6 +200,20 @@ func (m *Mock) On(method string, URL string, body []byte) *Request {
 	return expected
 }
 
+// NewMockT creates a new [Mock] that uses a test, and registers a cleanup
+// function that asserts its expectations when the test completes.
+func NewMockT(t testing.TB) *Mock {
+	t.Helper()
+
+	m := new(Mock).Test(t)
+	t.Cleanup(func() {
+		t.Helper()
+
+		m.AssertExpectations(t)
+	})
+	return m
+}
+
 // Test sets the test struct variable of the [Mock] object.
 func (m *Mock) Test(t mock.TestingT) *Mock {
 	m.mutex.Lock()
@@ -67,6 +222,84 @@ func (m *Mock) Test(t mock.TestingT) *Mock {
 	return m
 }
 
+// OnUnmatched sets the fallback expectation, whose response is returned for any
+// request that does not match an expectation registered with [Mock.On],
+// instead of failing. This includes requests for expectations that have
+// already been received as many times as expected, but not requests that are
+// out of order. Any response, including a passthrough [ResponseWriter]
+// set with [Request.RespondUsing], may be configured on the returned
+// [Request]. Requests handled by the fallback are recorded in
+// [Mock.Requests] and marked as unmatched, and are reported as failures by
+// [Mock.AssertExpectations] unless [Mock.TolerateUnmatched] is set.
+//
+//	Mock.OnUnmatched().Respond(http.StatusNotFound, []byte(`{"error": "not found"}`))
+func (m *Mock) OnUnmatched() *Request {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.fallback == nil {
+		m.fallback = newRequest(m, AnyMethod, &url.URL{}, AnyBody)
+	}
+	return m.fallback
+}
+
+// TolerateUnmatched indicates that requests handled by the fallback
+// expectation set with [Mock.OnUnmatched] should be reported as warnings
+// rather than failures by [Mock.AssertExpectations].
+func (m *Mock) TolerateUnmatched() *Mock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.tolerateUnmatched = true
+	return m
+}
+
+// StrictOrder indicates that every expectation must be satisfied in the order
+// in which it was registered with [Mock.On]. A request that matches an
+// expectation before the expectation registered ahead of it has been satisfied
+// is treated as out of order.
+func (m *Mock) StrictOrder() *Mock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.strictOrder = true
+	return m
+}
+
+// Seed seeds the source of randomness used for random response delays, so
+// that the delays set with [Response.DelayBetween] are reproducible.
+func (m *Mock) Seed(seed uint64) *Mock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.rand = rand.New(rand.NewPCG(seed, seed))
+	return m
+}
+
+// randomDuration returns a random duration between minDelay and maxDelay
+// inclusive. The [Mock]'s mutex must be held by the caller.
+func (m *Mock) randomDuration(minDelay time.Duration, maxDelay time.Duration) time.Duration {
+	if maxDelay <= minDelay {
+		return minDelay
+	}
+	if m.rand == nil {
+		m.rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return minDelay + time.Duration(m.rand.Int64N(int64(maxDelay-minDelay)+1))
+}
+
+// randomUUID returns a random version 4 UUID. The [Mock]'s mutex must be held
+// by the caller.
+func (m *Mock) randomUUID() string {
+	if m.rand == nil {
+		m.rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], m.rand.Uint64())
+	binary.BigEndian.PutUint64(b[8:], m.rand.Uint64())
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
 // fail the current test with the given formatted format and args. In the case
 // that a testing object was defined, it uses the test APIs for failing a test;
 // otherwise, it uses panic.
@@ -103,7 +336,7 @@ func (m *Mock) findExpectedRequest(actual *http.Request) (int, *Request) {
 		}
 
 		expected = er
-		if er.repeatability > -1 {
+		if !er.exhausted() && er.unsatisfiedPrerequisite() == nil {
 			return i, er
 		}
 	}
@@ -111,6 +344,25 @@ func (m *Mock) findExpectedRequest(actual *http.Request) (int, *Request) {
 	return -1, expected
 }
 
+// findOutOfOrderRequest finds an expectation that matches a received request
+// and has not been exhausted, but may not be matched yet because one of its
+// prerequisites has not been satisfied. The expectation and the prerequisite
+// are returned, or nil if there is no such expectation.
+func (m *Mock) findOutOfOrderRequest(actual *http.Request) (*Request, *Request) {
+	for _, er := range m.ExpectedRequests {
+		if er.exhausted() {
+			continue
+		}
+		if _, d := er.diff(actual); d != 0 {
+			continue
+		}
+		if prerequisite := er.unsatisfiedPrerequisite(); prerequisite != nil {
+			return er, prerequisite
+		}
+	}
+	return nil, nil
+}
+
 // findClosestRequest finds the first [Request] that most closely matches a
 // received [http.Request].
 //
@@ -136,23 +388,80 @@ func (m *Mock) findClosestRequest(received *http.Request) (*Request, string) {
 }
 
 // Requested tells the mock that a [http.Request] has been received and gets a
-// response to return. Panics if the request is unexpected (i.e. not preceded
-// by appropriate [Mock.On] calls).
+// response to return. Fails the test if the request is unexpected (i.e. not
+// preceded by appropriate [Mock.On] calls), or panics if no test struct was
+// set with [Mock.Test].
+//
+// Requested must only be called from the test's goroutine. The default
+// [Server] handler and [Transport] instead record failures, which are reported
+// by [Mock.AssertExpectations]. Custom handlers should use [Mock.RequestedE].
+//
+// If the received request's context is done while waiting for the hooks set
+// with [Request.NotifyOn] or [Request.WaitUntil], nil is returned.
 func (m *Mock) Requested(received *http.Request) *Response {
+	response, err := m.requested(received)
+	if err != nil && !errors.Is(err, ErrResponseCanceled) {
+		m.fail("%s", err)
+	}
+	return response
+}
+
+// RequestedE tells the mock that a [http.Request] has been received and gets a
+// response to return, like [Mock.Requested]. Instead of failing the test, the
+// error is recorded as a failure and returned, so RequestedE may be called
+// from any goroutine, such as the handler set with [ServerConfig.Handler].
+// Recorded failures are reported by [Mock.AssertExpectations]. An error
+// wrapping [ErrResponseCanceled] is returned, but not recorded.
+func (m *Mock) RequestedE(received *http.Request) (*Response, error) {
+	response, err := m.requested(received)
+	if err != nil && !errors.Is(err, ErrResponseCanceled) {
+		m.recordFailure(err)
+	}
+	return response, err
+}
+
+// requested tells the mock that a [http.Request] has been received and gets a
+// response to return. An error wrapping [ErrUnexpectedRequest] or
+// [ErrReadBody] is returned if the request cannot be matched. Once the request
+// has been matched and recorded, the hooks of the matched expectation are
+// invoked, which may return an error wrapping [ErrResponseCanceled].
+func (m *Mock) requested(received *http.Request) (*Response, error) {
+	response, _, err := m.receive(received)
+	return response, err
+}
+
+// receive is like [Mock.requested], but also returns the sequence number of
+// the request recorded in [Mock.Requests], or 0 if it was not recorded, so that
+// the response written for it may be recorded with [Mock.recordWritten].
+func (m *Mock) receive(received *http.Request) (*Response, int, error) {
 	m.mutex.Lock()
 
 	receivedBody, err := SafeReadBody(received)
 	if err != nil {
 		m.mutex.Unlock()
-		m.fail("\nassert: httpmock: Failed to read requested body. Error: %v", err)
+		return nil, 0, newRequestError(ErrReadBody, "\nassert: httpmock: Failed to read requested body. Error: %v", err)
 	}
 
 	found, expected := m.findExpectedRequest(received)
 	if found < 0 {
+		// Expected request found, but its prerequisites have not been satisfied
+		if outOfOrder, prerequisite := m.findOutOfOrderRequest(received); outOfOrder != nil {
+			m.mutex.Unlock()
+			return nil, 0, newRequestError(ErrOutOfOrder, "\nassert: httpmock: The request was received out of order.\n\t%s %s\n\tmust not be requested until the following request is satisfied:\n\t%s %s\n",
+				outOfOrder.method, outOfOrder.urlString(),
+				prerequisite.method, prerequisite.urlString(),
+			)
+		}
+
+		// Respond with the fallback response instead of failing, if one was
+		// configured
+		if m.fallback != nil {
+			return m.respond(m.fallback, received, receivedBody, true)
+		}
 		// Expected request found, but has already been requested with repeatable times
 		if expected != nil {
 			m.mutex.Unlock()
-			m.fail("\nassert: httpmock: The request has been called over %d times.\n\tEither do one more Mock.On(%q, %q), or remove extra request.", expected.totalRequests, received.Method, received.URL.String())
+			return nil, 0, newRequestError(ErrUnexpectedRequest, "\nassert: httpmock: The request has been called over %d times.\n\tEither do one more Mock.On(%q, %q), or remove extra request.", expected.totalRequests, received.Method, received.URL.String())
 		}
 		// We have to fail here - because we don't know what to do for the
 		// response. This is becuase:
@@ -174,14 +483,13 @@ func (m *Mock) Requested(received *http.Request) *Response {
 			tempStr := "\t" + strings.Join(strings.Split(tempRequest.String(), "\n"), "\n\t")
 			closestStr := "\t" + strings.Join(strings.Split(closest.String(), "\n"), "\n\t")
 
-			m.fail("\n\nhttpmock: Unexpected Request\n-----------------------------\n\n%s\n\nThe closest request I have is: \n\n%s\nDiff: %s\n",
+			return nil, 0, newRequestError(ErrUnexpectedRequest, "\n\nhttpmock: Unexpected Request\n-----------------------------\n\n%s\n\nThe closest request I have is: \n\n%s\nDiff: %s\n",
 				tempStr,
 				closestStr,
 				strings.TrimSpace(mismatch),
 			)
-		} else {
-			m.fail("\nassert: httpmock: I don't know what to return because the request was unexpected.\n\tEither do Mock.On(%q, %q), or remove the request.\n", received.Method, received.URL.String())
 		}
+		return nil, 0, newRequestError(ErrUnexpectedRequest, "\nassert: httpmock: I don't know what to return because the request was unexpected.\n\tEither do Mock.On(%q, %q), or remove the request.\n", received.Method, received.URL.String())
 	}
 
 	if expected.repeatability == 1 {
@@ -189,18 +497,83 @@ func (m *Mock) Requested(received *http.Request) *Response {
 	} else if expected.repeatability > 1 {
 		expected.repeatability--
 	}
+
+	return m.respond(expected, received, receivedBody, false)
+}
+
+// respond gets the next response of an expectation for a received request,
+// records the received request, and invokes the expectation's hooks. The
+// [Mock]'s mutex must be held by the caller, and is released before the hooks
+// are invoked.
+func (m *Mock) respond(expected *Request, received *http.Request, receivedBody []byte, unmatched bool) (*Response, int, error) {
+	response := expected.nextResponse()
 	expected.totalRequests++
+	expected.setScenarioState()
 
 	// Add a clean request to received request list
-	newRequest := newRequest(m, received.Method, received.URL, receivedBody)
-	if expected.response != nil {
-		newResponse := *expected.response
-		newRequest.response = &newResponse
+	newRequest := newReceivedRequest(m, received, receivedBody)
+	newRequest.pathValues = expected.setPathValues(received)
+	newRequest.unmatched = unmatched
+	if response != nil {
+		newRequest.response = response.clone()
 	}
+	m.received++
+	newRequest.seq = m.received
 	m.Requests = append(m.Requests, *newRequest)
+	m.notifyChanged()
+	hooks := expected.hooks
 	m.mutex.Unlock()
 
-	return expected.response
+	if err := hooks.invoke(received, receivedBody); err != nil {
+		return nil, newRequest.seq, err
+	}
+	return response, newRequest.seq, nil
+}
+
+// recordWritten records the response that was written for the request with
+// the provided sequence number, if it is still in [Mock.Requests].
+func (m *Mock) recordWritten(seq int, written *writtenResponse) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i := len(m.Requests) - 1; i >= 0; i-- {
+		if m.Requests[i].seq == seq {
+			m.Requests[i].written = written
+			return
+		}
+	}
+}
+
+// recordFailure records an error that occurred while handling a request on a
+// goroutine other than the test's, so that it may be reported later on the
+// test's goroutine.
+func (m *Mock) recordFailure(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.failures = append(m.failures, err)
+}
+
+// Failures returns the errors that occurred while handling requests on a
+// goroutine other than the test's, such as unexpected requests received by a
+// [Server] or [Transport].
+func (m *Mock) Failures() []error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]error{}, m.failures...)
+}
+
+// reportFailures reports any failures that have not been reported yet to the
+// test, and returns whether any failures have occurred. The [Mock]'s mutex
+// must be held by the caller.
+func (m *Mock) reportFailures(t mock.TestingT) bool {
+	for _, err := range m.failures[m.reportedFailures:] {
+		t.Errorf("%s", err)
+	}
+	m.reportedFailures = len(m.failures)
+
+	return len(m.failures) == 0
 }
 
 // matchCandidate holds details about possible [Request] matches for a received
@@ -238,6 +611,199 @@ func (mc matchCandidate) isBetterMatchThan(other matchCandidate) bool {
 	return false
 }
 
+// Calls returns a snapshot of the requests that have been received, in the
+// order that they were received. The snapshot is safe to inspect while the
+// [Mock] continues to receive requests.
+func (m *Mock) Calls() []Request {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	calls := make([]Request, 0, len(m.Requests))
+	for _, actual := range m.Requests {
+		calls = append(calls, actual.snapshot())
+	}
+	return calls
+}
+
+// LastRequest returns a snapshot of the most recently received request, or nil
+// if no requests have been received.
+func (m *Mock) LastRequest() *Request {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.Requests) == 0 {
+		return nil
+	}
+	last := m.Requests[len(m.Requests)-1].snapshot()
+	return &last
+}
+
+// RequestsFor returns a snapshot of the received requests that match the
+// provided method and path. [AnyMethod] may be used to match requests with any
+// method. Like [Mock.AssertNumberOfRequests], URL username/password
+// information, query parameters, and fragment are ignored. If the path cannot
+// be parsed, no requests are returned.
+func (m *Mock) RequestsFor(method string, path string) []Request {
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.requestsFor(method, u)
+}
+
+// Expectations returns a snapshot of the expected requests that have been
+// registered with [Mock.On], in the order that they were registered.
+func (m *Mock) Expectations() []Request {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	expectations := make([]Request, 0, len(m.ExpectedRequests))
+	for _, expected := range m.ExpectedRequests {
+		expectations = append(expectations, expected.snapshot())
+	}
+	return expectations
+}
+
+// Reset removes every expectation, clears the received requests, and moves
+// every scenario back to [ScenarioStarted], so that the [Mock] may be reused as
+// if it were new. The test struct, strict order mode, seed, and recorded
+// failures are kept, so that failures are still reported to the test. It is
+// safe to call while a [Server] is serving requests.
+func (m *Mock) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.resetExpectations()
+	m.resetRequests()
+	m.scenarios = nil
+}
+
+// ResetRequests clears the received requests. Expectations, including the
+// number of times they have been received, and recorded failures are kept. It
+// is safe to call while a [Server] is serving requests.
+func (m *Mock) ResetRequests() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.resetRequests()
+}
+
+// ResetExpectations removes every expectation that has been registered with
+// [Mock.On], including the fallback expectation, unless it proxies requests to
+// the upstream of a [Server] created with [ServerConfig.Upstream]. Received
+// requests are kept. It is safe to call while a [Server] is serving requests.
+func (m *Mock) ResetExpectations() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.resetExpectations()
+}
+
+// resetRequests clears the received requests. Recorded failures are kept, so
+// that any that have not been reported yet are not lost. The [Mock]'s mutex
+// must be held by the caller.
+func (m *Mock) resetRequests() {
+	m.Requests = nil
+}
+
+// resetExpectations removes every expectation, and the fallback expectation
+// unless it should be kept. The [Mock]'s mutex must be held by the caller.
+func (m *Mock) resetExpectations() {
+	m.ExpectedRequests = nil
+	if !m.keepFallback {
+		m.fallback = nil
+	}
+	m.notifyChanged()
+}
+
+// notifyChanged wakes any goroutines waiting in [Mock.waitUntil]. The [Mock]'s
+// mutex must be held by the caller.
+func (m *Mock) notifyChanged() {
+	if m.changed != nil {
+		close(m.changed)
+		m.changed = nil
+	}
+}
+
+// waitUntil blocks until cond returns true or the context is done, and
+// returns whether cond returned true. cond is called with the [Mock]'s mutex
+// held, once immediately and again each time the [Mock] changes.
+func (m *Mock) waitUntil(ctx context.Context, cond func() bool) bool {
+	for {
+		m.mutex.Lock()
+		if cond() {
+			m.mutex.Unlock()
+			return true
+		}
+		if m.changed == nil {
+			m.changed = make(chan struct{})
+		}
+		changed := m.changed
+		m.mutex.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// WaitFor blocks until a request that matches the provided method and path has
+// been received, and returns a snapshot of the first such request. Like
+// [Mock.RequestsFor], [AnyMethod] may be used to match requests with any
+// method, and URL username/password information, query parameters, and
+// fragment are ignored. An error is returned if the context is done first or
+// the path cannot be parsed.
+//
+//	req, err := Mock.WaitFor(ctx, http.MethodPost, "/webhooks")
+func (m *Mock) WaitFor(ctx context.Context, method string, path string) (*Request, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse path %q into URL: %w", path, err)
+	}
+
+	var found *Request
+	ok := m.waitUntil(ctx, func() bool {
+		if requests := m.requestsFor(method, u); len(requests) > 0 {
+			found = &requests[0]
+			return true
+		}
+		return false
+	})
+	if !ok {
+		return nil, fmt.Errorf("waiting for %s %s: %w", method, path, context.Cause(ctx))
+	}
+	return found, nil
+}
+
+// AssertExpectationsWithin waits up to the provided timeout for everything
+// specified with [Mock.On] to be requested as expected, then asserts the
+// expectations like [Mock.AssertExpectations]. It returns as soon as the
+// expectations are met, so it may be used to wait for requests that are sent
+// asynchronously.
+func (m *Mock) AssertExpectationsWithin(t mock.TestingT, timeout time.Duration) bool {
+	if th, ok := t.(tHelper); ok {
+		th.Helper()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	m.waitUntil(ctx, func() bool {
+		for _, er := range m.ExpectedRequests {
+			if satisfied, _ := m.checkExpectation(er); !satisfied {
+				return false
+			}
+		}
+		return true
+	})
+	return m.AssertExpectations(t)
+}
+
 // AssertExpectations assert that everything specified with [Mock.On] and
 // [Request.Respond] was in fact requested as expected. [Request]'s may have
 // occurred in any order.
@@ -249,6 +815,12 @@ func (m *Mock) AssertExpectations(t mock.TestingT) bool {
 	defer m.mutex.Unlock()
 	var failedExpectations int
 
+	// Report requests that failed on another goroutine
+	noFailures := m.reportFailures(t)
+
+	// Report requests that were handled by the fallback expectation
+	noUnmatched := m.reportUnmatched(t)
+
 	// Iterate through each expectation
 	expectedRequests := m.expectedRequests()
 	for _, er := range expectedRequests {
@@ -262,7 +834,62 @@ func (m *Mock) AssertExpectations(t mock.TestingT) bool {
 		t.Errorf("FAIL: %d out of %d expectation(s) were met.\n\tThe code you are testing needs to make %d more requests(s).", len(expectedRequests)-failedExpectations, len(expectedRequests), failedExpectations)
 	}
 
-	return failedExpectations == 0
+	return failedExpectations == 0 && noFailures && noUnmatched
+}
+
+// reportUnmatched reports the received requests that were handled by the
+// fallback expectation, and returns whether there were none. If
+// [Mock.TolerateUnmatched] is set, they are logged as warnings and true is
+// returned. The [Mock]'s mutex must be held by the caller.
+func (m *Mock) reportUnmatched(t mock.TestingT) bool {
+	var unmatched int
+	for _, actual := range m.Requests {
+		if !actual.unmatched {
+			continue
+		}
+		unmatched++
+
+		status := "FAIL"
+		if m.tolerateUnmatched {
+			status = "WARNING"
+		}
+		t.Logf("%s:\t%s %s (unmatched)\n\t(%d) %s", status, actual.method, actual.url, len(actual.body), trimBody(actual.body))
+	}
+
+	if unmatched == 0 || m.tolerateUnmatched {
+		return true
+	}
+	t.Errorf("FAIL: %d unmatched request(s) were received.\n\tEither do Mock.On for each request, or use Mock.TolerateUnmatched.", unmatched)
+	return false
+}
+
+// UnmatchedRequests returns a snapshot of the received requests that were
+// handled by the fallback expectation set with [Mock.OnUnmatched], in the
+// order that they were received.
+func (m *Mock) UnmatchedRequests() []Request {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var unmatched []Request
+	for _, actual := range m.Requests {
+		if actual.unmatched {
+			unmatched = append(unmatched, actual.snapshot())
+		}
+	}
+	return unmatched
+}
+
+// AssertNoFailures asserts that no errors occurred while handling requests on
+// a goroutine other than the test's, such as unexpected requests received by a
+// [Server] or [Transport]. Each error is only reported once.
+func (m *Mock) AssertNoFailures(t mock.TestingT) bool {
+	if th, ok := t.(tHelper); ok {
+		th.Helper()
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.reportFailures(t)
 }
 
 // AssertNumberOfRequests asserts that the request was made expectedRequests times.
@@ -277,38 +904,46 @@ func (m *Mock) AssertNumberOfRequests(t mock.TestingT, method string, path strin
 		th.Helper()
 	}
 
-	// Remove parts of the URL for the purposes of general comparison
 	u, err := url.Parse(path)
 	if err != nil {
 		t.Errorf("FAIL: unable to parse path %q into URL: %v", path, err)
 		t.FailNow()
 	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	actualRequests := len(m.requestsFor(method, u))
+
+	return assert.Equal(t, expectedRequests, actualRequests)
+}
+
+// generalURL removes the parts of a URL that are ignored for the purposes of
+// general comparison, and returns the remaining URL as a string.
+func generalURL(u url.URL) string {
 	u.User = nil
 	u.RawQuery = ""
 	u.Fragment = ""
 	u.RawFragment = ""
-	path = u.String()
+	return u.String()
+}
 
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-	var actualRequests int
+// requestsFor finds the received [Request]'s that generally match the
+// provided method and URL. URL username/password information, query
+// parameters, and fragment are ignored.
+func (m *Mock) requestsFor(method string, u *url.URL) []Request {
+	path := generalURL(*u)
+
+	var found []Request
 	for _, actual := range m.requests() {
-		if actual.method != method {
+		if method != AnyMethod && actual.method != method {
 			continue
 		}
-
-		u := *actual.url
-		u.User = nil
-		u.RawQuery = ""
-		u.Fragment = ""
-		if u.String() != path {
+		if generalURL(*actual.url) != path {
 			continue
 		}
-
-		actualRequests++
+		found = append(found, actual.snapshot())
 	}
-
-	return assert.Equal(t, expectedRequests, actualRequests)
+	return found
 }
 
 // AssertRequested asserts that the request was received.
@@ -325,7 +960,7 @@ func (m *Mock) AssertRequested(t mock.TestingT, method string, path string, body
 
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	if !m.checkWasRequested(method, u, body) {
+	if !m.checkWasRequested(method, u, body, true) {
 		tempRequest := newRequest(m, method, u, body)
 		v := "\t" + strings.Join(strings.Split(tempRequest.String(), "\n"), "\n\t")
 		return assert.Fail(
@@ -351,7 +986,7 @@ func (m *Mock) AssertNotRequested(t mock.TestingT, method string, path string, b
 
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	if m.checkWasRequested(method, u, body) {
+	if m.checkWasRequested(method, u, body, true) {
 		tempRequest := newRequest(m, method, u, body)
 		v := "\t" + strings.Join(strings.Split(tempRequest.String(), "\n"), "\n\t")
 		return assert.Fail(
@@ -364,22 +999,33 @@ func (m *Mock) AssertNotRequested(t mock.TestingT, method string, path string, b
 }
 
 // checkExpectation checks whether an expected [Request] was received,
-// whether it received the expected number of times.
+// whether it received the expected number of times, and whether its sequence
+// of responses has been returned.
 func (m *Mock) checkExpectation(expected *Request) (bool, string) {
-	if (!m.checkWasRequested(expected.method, expected.url, expected.body) && expected.totalRequests == 0) || (expected.repeatability > 0) {
+	met := expected.satisfied()
+	if !met && !expected.countRange && expected.totalRequests == 0 && expected.repeatability == 0 && expected.sequenceLength() == 0 {
+		// The request may have been matched by another expectation
+		met = m.checkWasRequested(expected.method, expected.url, expected.body, false)
+	}
+	if !met {
 		return false, fmt.Sprintf("FAIL:\t%s %s\n\t(%d) %s", expected.method, expected.url, len(expected.body), trimBody(expected.body))
 	}
 	return true, fmt.Sprintf("PASS:\t%s %s\n\t(%d) %s", expected.method, expected.url, len(expected.body), trimBody(expected.body))
 }
 
 // checkWasRequested checks whether a set of [Request] parameters was received.
-func (m *Mock) checkWasRequested(method string, URL *url.URL, body []byte) bool {
+// Requests handled by the fallback expectation are only considered if
+// includeUnmatched is set.
+func (m *Mock) checkWasRequested(method string, URL *url.URL, body []byte, includeUnmatched bool) bool {
 	tempReceived := &http.Request{
 		Method: method,
 		URL:    URL,
 		Body:   io.NopCloser(bytes.NewReader(body)),
 	}
 	for _, actual := range m.requests() {
+		if actual.unmatched && !includeUnmatched {
+			continue
+		}
 		if _, d := actual.diff(tempReceived); d == 0 {
 			return true
 		}
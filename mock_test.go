@@ -1,14 +1,17 @@
 package httpmock
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -39,6 +42,52 @@ func (m *MockTestingT) FailNow() {
 
 func (m *MockTestingT) Helper() {}
 
+// MockTestingTNoFailNow mocks a test struct whose FailNow does not stop the
+// execution, as is allowed by some [mock.TestingT] implementations.
+type MockTestingTNoFailNow struct {
+	MockTestingT
+}
+
+func (m *MockTestingTNoFailNow) FailNow() {
+	m.failNowCount++
+}
+
+// MockTB mocks a testing.TB, recording failures with MockTestingT and
+// collecting cleanup functions so that a test may run them explicitly.
+type MockTB struct {
+	testing.TB
+	MockTestingT
+
+	cleanups []func()
+}
+
+func (m *MockTB) Logf(format string, args ...interface{}) {
+	m.MockTestingT.Logf(format, args...)
+}
+
+func (m *MockTB) Errorf(format string, args ...interface{}) {
+	m.MockTestingT.Errorf(format, args...)
+}
+
+func (m *MockTB) FailNow() {
+	m.MockTestingT.FailNow()
+}
+
+func (m *MockTB) Helper() {}
+
+func (m *MockTB) Cleanup(f func()) {
+	m.cleanups = append(m.cleanups, f)
+}
+
+// runCleanups runs the registered cleanup functions in last-added,
+// first-called order, as the testing package does.
+func (m *MockTB) runCleanups() {
+	for i := len(m.cleanups) - 1; i >= 0; i-- {
+		m.cleanups[i]()
+	}
+	m.cleanups = nil
+}
+
 // mustNewRequest is a convenience test helper that wraps a call to
 // http.NewRequest() and panics if an error is returned. It is only
 // intended to be used during test setup.
@@ -51,6 +100,21 @@ func mustNewRequest(request *http.Request, err error) *http.Request {
 	return request
 }
 
+func TestMock_Seed(t *testing.T) {
+	// Setup
+	m1 := new(Mock).Seed(1234)
+	m2 := new(Mock).Seed(1234)
+
+	// Test and Assertions
+	for range 10 {
+		got := m1.randomDuration(time.Millisecond, time.Second)
+		assert.Equal(t, m2.randomDuration(time.Millisecond, time.Second), got)
+		assert.GreaterOrEqual(t, got, time.Millisecond)
+		assert.LessOrEqual(t, got, time.Second)
+	}
+	assert.Equal(t, time.Second, m1.randomDuration(time.Second, time.Second))
+}
+
 func TestMock_fail_NoTestingT(t *testing.T) {
 	// Setup
 	var successfulCall int
@@ -115,6 +179,239 @@ func TestMock_On(t *testing.T) {
 	assert.Equal(t, want, m.ExpectedRequests[0])
 }
 
+func TestMock_OnJSON_BadBody(t *testing.T) {
+	// Setup
+	var successfulRequestedCall int
+
+	mockT := new(MockTestingT)
+	m := new(Mock).Test(mockT)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Did not expect to get here")
+		}
+		// Assertions
+		assert.Equal(t, "FailNow was called", r.(string))
+		assert.Equal(t, 1, mockT.failNowCount)
+		assert.Zero(t, successfulRequestedCall)
+	}()
+
+	// Test
+	m.OnJSON(http.MethodPost, "https://test.com/foo", []byte(`{"foo":`))
+	successfulRequestedCall++
+}
+
+func TestMock_OnJSON(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.OnJSON(http.MethodPost, "https://test.com/foo", []byte(`{"foo": "bar", "items": [1, 2]}`)).RespondNoContent()
+	m.OnJSON(http.MethodPut, "https://test.com/foo", []byte(`{"foo": "bar"}`)).AllowExtraFields().RespondNoContent()
+
+	// Test and Assertions
+	got := m.Requested(mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/foo", strings.NewReader(`{"items":[1,2],"foo":"bar"}`))))
+	assert.Equal(t, http.StatusNoContent, got.statusCode)
+
+	got = m.Requested(mustNewRequest(http.NewRequest(http.MethodPut, "https://test.com/foo", strings.NewReader(`{"id": 1, "foo": "bar"}`))))
+	assert.Equal(t, http.StatusNoContent, got.statusCode)
+
+	index, _ := m.findExpectedRequest(mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/foo", strings.NewReader(`{"id": 1, "foo": "bar", "items": [1, 2]}`))))
+	assert.Equal(t, -1, index)
+}
+
+func TestMock_On_BadPathTemplate(t *testing.T) {
+	// Setup
+	var successfulRequestedCall int
+
+	mockT := new(MockTestingT)
+	m := new(Mock).Test(mockT)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Did not expect to get here")
+		}
+		// Assertions
+		assert.Equal(t, "FailNow was called", r.(string))
+		assert.Equal(t, 1, mockT.failNowCount)
+		assert.Zero(t, successfulRequestedCall)
+	}()
+
+	// Test
+	m.On(http.MethodGet, "/users/{id}/{id}", nil)
+	successfulRequestedCall++
+}
+
+func TestMock_On_BadURL_NoFailNow(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingTNoFailNow)
+	m := new(Mock).Test(mockT)
+
+	// Test
+	got := m.On(http.MethodGet, "https://test.com/%zz", nil).RespondOK(nil)
+
+	// Assertions
+	assert.NotNil(t, got)
+	assert.Equal(t, 1, mockT.errorfCount)
+	assert.Equal(t, 1, mockT.failNowCount)
+	assert.Empty(t, m.ExpectedRequests)
+}
+
+func TestMock_On_LiteralBraces(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(http.MethodGet, "https://test.com/x{y}", nil).RespondOK(nil)
+
+	// Test
+	index, _ := m.findExpectedRequest(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/x{y}", http.NoBody)))
+	otherIndex, _ := m.findExpectedRequest(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/xz", http.NoBody)))
+
+	// Assertions
+	assert.Nil(t, m.ExpectedRequests[0].pathPattern)
+	assert.Equal(t, 0, index)
+	assert.Equal(t, -1, otherIndex)
+}
+
+func TestMock_Requested_PathTemplate(t *testing.T) {
+	// Setup
+	var matcherID string
+	captureID := func(received *http.Request) (output string, differences int) {
+		if id := received.PathValue("id"); id != "" {
+			matcherID = id
+		}
+		return "PASS:  captured id", 0
+	}
+
+	m := new(Mock).Test(t)
+	m.On(http.MethodGet, "https://test.com/users/{id}", nil).Matches(captureID).RespondOK(nil)
+	m.On(http.MethodGet, "https://test.com/files/{path...}", nil).RespondOK(nil)
+
+	// Test
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/users/123", http.NoBody))
+	m.Requested(received)
+	m.Requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/files/a/b.txt", http.NoBody)))
+
+	// Assertions
+	assert.Equal(t, "123", matcherID)
+	assert.Equal(t, "123", received.PathValue("id"))
+	assert.Len(t, m.Requests, 2)
+	assert.Equal(t, "123", m.Requests[0].PathValue("id"))
+	assert.Equal(t, "a/b.txt", m.Requests[1].PathValue("path"))
+	assert.Empty(t, m.Requests[1].PathValue("id"))
+
+	index, _ := m.findExpectedRequest(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/users/123/friends", http.NoBody)))
+	assert.Equal(t, -1, index)
+}
+
+func TestMock_Requested_PathTemplate_Isolated(t *testing.T) {
+	// Setup
+	var leakedID string
+	captureLeak := func(received *http.Request) (output string, differences int) {
+		leakedID = received.PathValue("id")
+		return "PASS:  captured id", 0
+	}
+
+	m := new(Mock).Test(t)
+	m.On(http.MethodGet, "https://test.com/users/{id}", nil).Matches(testRequestMatcherAlwaysFail).RespondOK(nil)
+	m.On(http.MethodGet, "https://test.com/{org}/{repo}", nil).Matches(captureLeak).RespondOK(nil)
+
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/users/123", http.NoBody))
+
+	// Test
+	m.Requested(received)
+
+	// Assertions
+	assert.Empty(t, leakedID)
+	assert.Empty(t, received.PathValue("id"))
+	assert.Equal(t, "users", received.PathValue("org"))
+	assert.Equal(t, "123", received.PathValue("repo"))
+}
+
+func TestMock_OnPattern_BadPattern(t *testing.T) {
+	// Setup
+	var successfulRequestedCall int
+
+	mockT := new(MockTestingT)
+	m := new(Mock).Test(mockT)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Did not expect to get here")
+		}
+		// Assertions
+		assert.Equal(t, "FailNow was called", r.(string))
+		assert.Equal(t, 1, mockT.failNowCount)
+		assert.Zero(t, successfulRequestedCall)
+	}()
+
+	// Test
+	m.OnPattern(http.MethodGet, `/v[0-9+/health`, nil)
+	successfulRequestedCall++
+}
+
+func TestMock_OnPattern(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.OnPattern(http.MethodGet, `/v[0-9]+/health`, nil).RespondOK(nil)
+	m.OnPattern(http.MethodGet, `/users/(?P<id>[0-9]+)`, nil).HostGlob("*.internal").RespondOK(nil)
+	m.On(http.MethodGet, "/", nil).PathGlob("/files/*.txt").HostRegexp(`files\.[a-z]+`).RespondOK(nil)
+
+	tests := []struct {
+		name      string
+		request   *http.Request
+		wantIndex int
+	}{
+		{
+			name:      "path-regexp",
+			request:   mustNewRequest(http.NewRequest(http.MethodGet, "/v2/health", http.NoBody)),
+			wantIndex: 0,
+		},
+		{
+			name:      "path-regexp-mismatch",
+			request:   mustNewRequest(http.NewRequest(http.MethodGet, "/v2/health/check", http.NoBody)),
+			wantIndex: -1,
+		},
+		{
+			name: "host-glob",
+			request: func() *http.Request {
+				r := mustNewRequest(http.NewRequest(http.MethodGet, "/users/123", http.NoBody))
+				r.Host = "api.internal:8080"
+				return r
+			}(),
+			wantIndex: 1,
+		},
+		{
+			name: "host-glob-mismatch",
+			request: func() *http.Request {
+				r := mustNewRequest(http.NewRequest(http.MethodGet, "/users/123", http.NoBody))
+				r.Host = "api.external"
+				return r
+			}(),
+			wantIndex: -1,
+		},
+		{
+			name: "path-glob-host-regexp",
+			request: func() *http.Request {
+				r := mustNewRequest(http.NewRequest(http.MethodGet, "/files/notes.txt", http.NoBody))
+				r.Host = "files.example"
+				return r
+			}(),
+			wantIndex: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			gotIndex, _ := m.findExpectedRequest(tt.request)
+
+			// Assertions
+			assert.Equal(t, tt.wantIndex, gotIndex)
+		})
+	}
+}
+
 func TestMock_findExpectedRequest_Fail(t *testing.T) {
 	requestMatcherRequireNextToken := func(received *http.Request) (output string, differences int) {
 		if ok := received.URL.Query().Has("next"); !ok {
@@ -495,6 +792,70 @@ func TestMock_Requested(t *testing.T) {
 	assert.Equal(t, 1, got.parent.totalRequests)
 }
 
+func TestMock_RequestedE(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	m := new(Mock).Test(mockT)
+	m.On(http.MethodGet, "/foo", nil).RespondOK([]byte("foo"))
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, err := m.RequestedE(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		response.Write(w, r)
+	}))
+	defer s.Close()
+
+	// Test
+	gotOK, err := s.Client().Get(s.URL + "/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotOK.Body.Close()
+
+	gotNotFound, err := s.Client().Get(s.URL + "/bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotNotFound.Body.Close()
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, gotOK.StatusCode)
+	assert.Equal(t, http.StatusNotFound, gotNotFound.StatusCode)
+	assert.Zero(t, mockT.failNowCount)
+	if failures := m.Failures(); assert.Len(t, failures, 1) {
+		assert.ErrorIs(t, failures[0], ErrUnexpectedRequest)
+	}
+
+	m.AssertExpectations(mockT)
+	assert.Equal(t, 1, mockT.errorfCount)
+}
+
+func TestMock_Requested_RecordsMetadata(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(http.MethodPost, "https://test.com/foo", []byte(testBody)).RespondOK(nil)
+
+	received := mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/foo", strings.NewReader(testBody)))
+	received.Header.Set("Idempotency-Key", "abcd")
+	received.RemoteAddr = "10.0.0.1:1234"
+
+	// Test
+	m.Requested(received)
+
+	// Assertions
+	assert.Len(t, m.Requests, 1)
+	got := m.Requests[0]
+	assert.Equal(t, "abcd", got.Header().Get("Idempotency-Key"))
+	assert.Equal(t, "test.com", got.Host())
+	assert.Equal(t, "HTTP/1.1", got.Proto())
+	assert.Equal(t, "10.0.0.1:1234", got.RemoteAddr())
+	assert.Equal(t, int64(len(testBody)), got.ContentLength())
+	assert.Nil(t, got.TLS())
+}
+
 func TestMock_RequestedOnce(t *testing.T) {
 	// Setup
 	m := new(Mock).Test(t)
@@ -531,62 +892,385 @@ func TestMock_RequestedTimes(t *testing.T) {
 	assert.Equal(t, 1, got.parent.totalRequests)
 }
 
-func TestMock_AssertExpectations_NoMatch(t *testing.T) {
+func TestMock_Requested_After(t *testing.T) {
 	// Setup
-	var successfulRequestedCall int
+	m := new(Mock)
+	token := m.On(http.MethodPost, "https://test.com/token", nil).RespondOK([]byte("token")).Once()
+	m.On(http.MethodGet, "https://test.com/resource", nil).RespondOK([]byte("resource")).After(token)
+
+	tokenRequest := mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/token", http.NoBody))
+	resourceRequest := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/resource", http.NoBody))
 
+	// Test and Assertions
+	got, err := m.requested(resourceRequest)
+	assert.Nil(t, got)
+	assert.ErrorIs(t, err, ErrOutOfOrder)
+	assert.ErrorContains(t, err, "GET https://test.com/resource\n\tmust not be requested until the following request is satisfied:\n\tPOST https://test.com/token")
+	assert.Empty(t, m.Requests)
+
+	got, err = m.requested(tokenRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("token"), got.body)
+
+	got, err = m.requested(resourceRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("resource"), got.body)
+}
+
+func TestMock_Requested_OutOfOrderFails(t *testing.T) {
+	// Setup
 	mockT := new(MockTestingT)
 	m := new(Mock).Test(mockT)
-	m.On(http.MethodDelete, "test.com/foo/1234", nil).RespondOK([]byte(`{"foo": "bar"}`))
+	InOrder(
+		m.On(http.MethodPost, "https://test.com/token", nil),
+		m.On(http.MethodGet, "https://test.com/resource", nil),
+	)
 
-	defer func() {
-		r := recover()
-		if r == nil {
-			t.Fatal("Did not expect to get here")
-		}
-		// Assertions
-		assert.Equal(t, "FailNow was called", r.(string))
-		assert.Equal(t, 1, mockT.failNowCount)
-		assert.False(t, m.AssertExpectations(mockT))
-		assert.Zero(t, successfulRequestedCall)
-	}()
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/resource", http.NoBody))
 
-	assert.False(t, m.AssertExpectations(mockT))
+	// Test and Assertions
+	assert.PanicsWithValue(t, "FailNow was called", func() { m.Requested(received) })
+	assert.Equal(t, 1, mockT.errorfCount)
+	assert.Equal(t, 1, mockT.failNowCount)
+}
 
-	received := mustNewRequest(http.NewRequest(http.MethodGet, "test.com/foo/1234", http.NoBody))
+func TestMock_Requested_StrictOrder(t *testing.T) {
+	// Setup
+	m := new(Mock).StrictOrder()
+	m.On(http.MethodPost, "https://test.com/token", nil).RespondOK(nil).Once()
+	m.On(http.MethodGet, "https://test.com/resource", nil).RespondOK(nil).Twice()
+	m.On(http.MethodDelete, "https://test.com/resource", nil).RespondNoContent()
 
-	// Test
-	m.Requested(received)
-	successfulRequestedCall++
-}
+	tokenRequest := mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/token", http.NoBody))
+	getRequest := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/resource", http.NoBody))
+	deleteRequest := mustNewRequest(http.NewRequest(http.MethodDelete, "https://test.com/resource", http.NoBody))
 
-func makeEqualToRequestMatcher(key string, value string) RequestMatcher {
-	fn := func(received *http.Request) (output string, differences int) {
-		v := received.URL.Query().Get(key)
+	// Test and Assertions
+	_, err := m.requested(getRequest)
+	assert.ErrorIs(t, err, ErrOutOfOrder)
 
-		if !strings.EqualFold(value, v) {
-			output = fmt.Sprintf("FAIL:  query-parameter %s=%v != %v", key, v, value)
-			differences = 1
-			return
-		}
+	_, err = m.requested(tokenRequest)
+	assert.NoError(t, err)
 
-		output = fmt.Sprintf("PASS:  query-parameter %s=%v == %v", key, v, value)
-		return
-	}
+	_, err = m.requested(getRequest)
+	assert.NoError(t, err)
 
-	return fn
+	_, err = m.requested(deleteRequest)
+	assert.ErrorIs(t, err, ErrOutOfOrder)
+	assert.ErrorContains(t, err, "GET https://test.com/resource")
+
+	_, err = m.requested(getRequest)
+	assert.NoError(t, err)
+
+	_, err = m.requested(deleteRequest)
+	assert.NoError(t, err)
 }
 
-func TestMock_AssertExpectations(t *testing.T) {
+func TestMock_Requested_Run(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	var gotID, gotRequestID string
+	var gotBody []byte
+	m.On(http.MethodPost, "https://test.com/users/{id}", AnyBody).
+		RespondNoContent().
+		Run(func(r *http.Request) {
+			gotID = r.PathValue("id")
+			gotRequestID = r.Header.Get("X-Request-Id")
+			gotBody, _ = io.ReadAll(r.Body)
+		})
 
-	tests := []struct {
-		name            string
-		method          string
-		path            string
-		body            []byte
-		requestMatchers []RequestMatcher
-		received        *http.Request
-	}{
+	received := mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/users/1234", strings.NewReader(testBody)))
+	received.Header.Set("X-Request-Id", "abc")
+
+	// Test
+	got := m.Requested(received)
+
+	// Assertions
+	assert.NotNil(t, got)
+	assert.Equal(t, "1234", gotID)
+	assert.Equal(t, "abc", gotRequestID)
+	assert.Equal(t, []byte(testBody), gotBody)
+}
+
+func TestMock_Requested_RunResetsBody(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	var gotRunBody []byte
+	m.On(http.MethodPost, "https://test.com/users", AnyBody).
+		RespondUsing(func(w http.ResponseWriter, r *http.Request) (int, error) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				return 0, err
+			}
+			return w.Write(body)
+		}).
+		Run(func(r *http.Request) {
+			gotRunBody, _ = io.ReadAll(r.Body)
+		})
+
+	received := mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/users", strings.NewReader(testBody)))
+	recorder := httptest.NewRecorder()
+
+	// Test
+	_, err := m.Requested(received).Write(recorder, received)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(testBody), gotRunBody)
+	assert.Equal(t, testBody, recorder.Body.String())
+}
+
+func TestMock_Requested_WaitUntilCanceled(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	m := new(Mock).Test(mockT)
+	m.On(http.MethodGet, "https://test.com/foo", nil).RespondOK(nil).WaitUntil(make(chan time.Time))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	received := mustNewRequest(http.NewRequestWithContext(ctx, http.MethodGet, "https://test.com/foo", http.NoBody))
+
+	// Test
+	got := m.Requested(received)
+
+	// Assertions
+	assert.Nil(t, got)
+	assert.Zero(t, mockT.errorfCount)
+	assert.Len(t, m.Requests, 1)
+}
+
+func TestMock_Requested_Sequence(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     SequencePolicy
+		wantStatus []int
+	}{
+		{
+			name:       "repeat-last",
+			policy:     SequenceRepeatLast,
+			wantStatus: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:       "cycle",
+			policy:     SequenceCycle,
+			wantStatus: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK, http.StatusServiceUnavailable, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock).Test(t)
+			m.On(http.MethodGet, "https://test.com/foo", nil).
+				Respond(http.StatusServiceUnavailable, nil).
+				ThenRespond(http.StatusTooManyRequests, nil).
+				ThenRespondOK([]byte(testBody)).
+				AfterSequence(tt.policy)
+
+			received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody))
+
+			// Test
+			var gotStatus []int
+			for range tt.wantStatus {
+				gotStatus = append(gotStatus, m.Requested(received).StatusCode())
+			}
+
+			// Assertions
+			assert.Equal(t, tt.wantStatus, gotStatus)
+			assert.Len(t, m.Requests, len(tt.wantStatus))
+			for i, r := range m.Requests {
+				assert.Equal(t, tt.wantStatus[i], r.Response().StatusCode())
+			}
+		})
+	}
+}
+
+func TestMock_Requested_SequenceFail(t *testing.T) {
+	// Setup
+	var successfulRequestedCall int
+
+	mockT := &MockTestingT{}
+	m := new(Mock).Test(mockT)
+	m.On(http.MethodGet, "https://test.com/foo", nil).
+		Respond(http.StatusServiceUnavailable, nil).
+		ThenRespondOK(nil).
+		AfterSequence(SequenceFail)
+
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody))
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Did not expect to get here")
+		}
+		// Assertions
+		assert.Equal(t, "FailNow was called", r.(string))
+		assert.Equal(t, 1, mockT.failNowCount)
+		assert.Equal(t, 2, successfulRequestedCall)
+	}()
+
+	// Test
+	m.Requested(received)
+	successfulRequestedCall++
+	m.Requested(received)
+	successfulRequestedCall++
+	m.Requested(received)
+	successfulRequestedCall++
+}
+
+func TestMock_Calls(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(http.MethodGet, "https://test.com/foo", nil).RespondOK([]byte(testBody)).Header("next", "abcd")
+	m.On(http.MethodDelete, "https://test.com/foo", nil).RespondNoContent()
+
+	assert.Empty(t, m.Calls())
+
+	m.Requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody)))
+	m.Requested(mustNewRequest(http.NewRequest(http.MethodDelete, "https://test.com/foo", http.NoBody)))
+
+	// Test
+	got := m.Calls()
+
+	// Assertions
+	assert.Len(t, got, 2)
+	assert.Equal(t, http.MethodGet, got[0].Method())
+	assert.Equal(t, "https://test.com/foo", got[0].URL().String())
+	assert.Equal(t, http.StatusOK, got[0].Response().StatusCode())
+	assert.Equal(t, []byte(testBody), got[0].Response().Body())
+	assert.Equal(t, http.Header{"next": []string{"abcd"}}, got[0].Response().Headers())
+	assert.Equal(t, http.MethodDelete, got[1].Method())
+	assert.Equal(t, http.StatusNoContent, got[1].Response().StatusCode())
+
+	// Modifying the snapshot should not modify the mock
+	got[0].URL().Path = "/bar"
+	got[0].Response().header["next"][0] = "efgh"
+	assert.Equal(t, "/foo", m.Requests[0].url.Path)
+	assert.Equal(t, []string{"abcd"}, m.Requests[0].response.header["next"])
+	assert.Equal(t, []string{"abcd"}, m.ExpectedRequests[0].response.header["next"])
+}
+
+func TestMock_LastRequest(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(AnyMethod, "https://test.com/foo", nil).RespondOK(nil)
+
+	assert.Nil(t, m.LastRequest())
+
+	m.Requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody)))
+	m.Requested(mustNewRequest(http.NewRequest(http.MethodPut, "https://test.com/foo", http.NoBody)))
+
+	// Test
+	got := m.LastRequest()
+
+	// Assertions
+	if assert.NotNil(t, got) {
+		assert.Equal(t, http.MethodPut, got.Method())
+	}
+}
+
+func TestMock_RequestsFor(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(AnyMethod, "https://test.com/foo", nil).RespondOK(nil)
+	m.On(AnyMethod, "https://test.com/bar", AnyBody).RespondOK(nil)
+
+	m.Requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody)))
+	m.Requested(mustNewRequest(http.NewRequest(http.MethodPut, "https://test.com/bar", strings.NewReader(testBody))))
+	m.Requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo?page=2", http.NoBody)))
+
+	// Test and Assertions
+	got := m.RequestsFor(http.MethodGet, "https://test.com/foo")
+	assert.Len(t, got, 2)
+	assert.Equal(t, "", got[0].URL().RawQuery)
+	assert.Equal(t, "page=2", got[1].URL().RawQuery)
+
+	got = m.RequestsFor(AnyMethod, "https://test.com/bar")
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, []byte(testBody), got[0].Body())
+	}
+
+	assert.Empty(t, m.RequestsFor(http.MethodDelete, "https://test.com/foo"))
+	assert.Empty(t, m.RequestsFor(http.MethodGet, "https://^.com"))
+}
+
+func TestMock_Expectations(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(http.MethodGet, "https://test.com/foo", nil).RespondOK(nil).Twice()
+	m.On(http.MethodPost, "https://test.com/foo", []byte(testBody)).Respond(http.StatusCreated, nil)
+
+	m.Requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody)))
+
+	// Test
+	got := m.Expectations()
+
+	// Assertions
+	assert.Len(t, got, 2)
+	assert.Equal(t, http.MethodGet, got[0].Method())
+	assert.Equal(t, 1, got[0].TotalRequests())
+	assert.Equal(t, http.MethodPost, got[1].Method())
+	assert.Equal(t, []byte(testBody), got[1].Body())
+	assert.Equal(t, http.StatusCreated, got[1].Response().StatusCode())
+	assert.Zero(t, got[1].TotalRequests())
+}
+
+func TestMock_AssertExpectations_NoMatch(t *testing.T) {
+	// Setup
+	var successfulRequestedCall int
+
+	mockT := new(MockTestingT)
+	m := new(Mock).Test(mockT)
+	m.On(http.MethodDelete, "test.com/foo/1234", nil).RespondOK([]byte(`{"foo": "bar"}`))
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Did not expect to get here")
+		}
+		// Assertions
+		assert.Equal(t, "FailNow was called", r.(string))
+		assert.Equal(t, 1, mockT.failNowCount)
+		assert.False(t, m.AssertExpectations(mockT))
+		assert.Zero(t, successfulRequestedCall)
+	}()
+
+	assert.False(t, m.AssertExpectations(mockT))
+
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "test.com/foo/1234", http.NoBody))
+
+	// Test
+	m.Requested(received)
+	successfulRequestedCall++
+}
+
+func makeEqualToRequestMatcher(key string, value string) RequestMatcher {
+	fn := func(received *http.Request) (output string, differences int) {
+		v := received.URL.Query().Get(key)
+
+		if !strings.EqualFold(value, v) {
+			output = fmt.Sprintf("FAIL:  query-parameter %s=%v != %v", key, v, value)
+			differences = 1
+			return
+		}
+
+		output = fmt.Sprintf("PASS:  query-parameter %s=%v == %v", key, v, value)
+		return
+	}
+
+	return fn
+}
+
+func TestMock_AssertExpectations(t *testing.T) {
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		body            []byte
+		requestMatchers []RequestMatcher
+		received        *http.Request
+	}{
 		{
 			name:   "basic",
 			method: http.MethodGet,
@@ -662,7 +1346,11 @@ func TestMock_AssertExpectations(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Setup
 			m := new(Mock)
-			m.On(tt.method, tt.path, tt.body).Matches(tt.requestMatchers...).RespondOK([]byte(`{"foo": "bar"}`))
+			r := m.On(tt.method, tt.path, tt.body)
+			for _, fn := range tt.requestMatchers {
+				r.Matches(fn)
+			}
+			r.RespondOK([]byte(`{"foo": "bar"}`))
 
 			mockT := new(testing.T)
 			assert.False(t, m.AssertExpectations(mockT))
@@ -752,6 +1440,455 @@ func TestMock_AssertExpectations_Repeatability(t *testing.T) {
 	assert.True(t, m.AssertExpectations(mockT))
 }
 
+func TestMock_AssertExpectations_Sequence(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	m.On(http.MethodGet, "test.com/foo/1234", nil).
+		Respond(http.StatusServiceUnavailable, nil).
+		ThenRespond(http.StatusServiceUnavailable, nil).
+		ThenRespondOK([]byte(`{"foo": "bar"}`))
+
+	mockT := new(MockTestingT)
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "test.com/foo/1234", http.NoBody))
+
+	// Test and Assertions
+	m.Requested(received)
+	assert.False(t, m.AssertExpectations(mockT))
+
+	m.Requested(received)
+	assert.False(t, m.AssertExpectations(mockT))
+
+	m.Requested(received)
+	assert.True(t, m.AssertExpectations(mockT))
+}
+
+func TestMock_AssertExpectations_CountRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(r *Request)
+		requests  int
+		want      bool
+		wantFail  bool
+	}{
+		{
+			name:      "maybe-not-requested",
+			configure: func(r *Request) { r.Maybe() },
+			requests:  0,
+			want:      true,
+		},
+		{
+			name:      "maybe-requested",
+			configure: func(r *Request) { r.Maybe() },
+			requests:  3,
+			want:      true,
+		},
+		{
+			name:      "maybe-times-remaining",
+			configure: func(r *Request) { r.Maybe().Times(2) },
+			requests:  1,
+			want:      false,
+		},
+		{
+			name:      "at-least-not-met",
+			configure: func(r *Request) { r.AtLeast(2) },
+			requests:  1,
+			want:      false,
+		},
+		{
+			name:      "at-least-met",
+			configure: func(r *Request) { r.AtLeast(2) },
+			requests:  5,
+			want:      true,
+		},
+		{
+			name:      "at-most-not-requested",
+			configure: func(r *Request) { r.AtMost(2) },
+			requests:  0,
+			want:      true,
+		},
+		{
+			name:      "at-most-met",
+			configure: func(r *Request) { r.AtMost(2) },
+			requests:  2,
+			want:      true,
+		},
+		{
+			name:      "at-most-exceeded",
+			configure: func(r *Request) { r.AtMost(2) },
+			requests:  3,
+			want:      true,
+			wantFail:  true,
+		},
+		{
+			name:      "between-not-met",
+			configure: func(r *Request) { r.Between(2, 3) },
+			requests:  1,
+			want:      false,
+		},
+		{
+			name:      "between-met",
+			configure: func(r *Request) { r.Between(2, 3) },
+			requests:  3,
+			want:      true,
+		},
+		{
+			name:      "between-exceeded",
+			configure: func(r *Request) { r.Between(2, 3) },
+			requests:  4,
+			want:      true,
+			wantFail:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock)
+			r := m.On(http.MethodGet, "https://test.com/jobs/1234", nil)
+			r.RespondOK(nil)
+			tt.configure(r)
+
+			mockT := new(MockTestingT)
+			received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/jobs/1234", http.NoBody))
+
+			// Test
+			var gotFail bool
+			for range tt.requests {
+				if _, err := m.requested(received); err != nil {
+					assert.ErrorIs(t, err, ErrUnexpectedRequest)
+					gotFail = true
+				}
+			}
+			got := m.AssertExpectations(mockT)
+
+			// Assertions
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantFail, gotFail)
+		})
+	}
+}
+
+func TestMock_Reset(t *testing.T) {
+	tests := []struct {
+		name             string
+		reset            func(m *Mock)
+		wantExpectations int
+		wantRequests     int
+	}{
+		{
+			name:             "reset",
+			reset:            (*Mock).Reset,
+			wantExpectations: 0,
+			wantRequests:     0,
+		},
+		{
+			name:             "reset-requests",
+			reset:            (*Mock).ResetRequests,
+			wantExpectations: 1,
+			wantRequests:     0,
+		},
+		{
+			name:             "reset-expectations",
+			reset:            (*Mock).ResetExpectations,
+			wantExpectations: 0,
+			wantRequests:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			mockT := new(MockTestingT)
+			m := new(Mock).Test(mockT).StrictOrder()
+			m.On(http.MethodGet, "https://test.com/foo", nil).RespondOK(nil)
+
+			m.Requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody)))
+			_, err := m.requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/bar", http.NoBody)))
+			m.recordFailure(err)
+
+			// Test
+			tt.reset(m)
+
+			// Assertions
+			assert.Len(t, m.ExpectedRequests, tt.wantExpectations)
+			assert.Len(t, m.Requests, tt.wantRequests)
+			assert.Len(t, m.Failures(), 1)
+			assert.Same(t, mockT, m.test)
+			assert.True(t, m.strictOrder)
+		})
+	}
+}
+
+func TestMock_WaitFor(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	m.On(http.MethodPost, "https://test.com/webhooks", AnyBody).RespondNoContent()
+
+	go func() {
+		m.Requested(mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/webhooks?id=1", strings.NewReader(testBody))))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Test
+	got, err := m.WaitFor(ctx, http.MethodPost, "https://test.com/webhooks")
+
+	// Assertions
+	assert.NoError(t, err)
+	if assert.NotNil(t, got) {
+		assert.Equal(t, http.MethodPost, got.Method())
+		assert.Equal(t, []byte(testBody), got.Body())
+	}
+}
+
+func TestMock_WaitFor_Fail(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		wantError string
+	}{
+		{
+			name:      "timeout",
+			path:      "https://test.com/webhooks",
+			wantError: "waiting for POST https://test.com/webhooks: context deadline exceeded",
+		},
+		{
+			name:      "invalid-path",
+			path:      "://test.com/webhooks",
+			wantError: `unable to parse path "://test.com/webhooks" into URL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock)
+			ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+			defer cancel()
+
+			// Test
+			got, err := m.WaitFor(ctx, http.MethodPost, tt.path)
+
+			// Assertions
+			assert.Nil(t, got)
+			assert.ErrorContains(t, err, tt.wantError)
+		})
+	}
+}
+
+func TestMock_AssertExpectationsWithin(t *testing.T) {
+	tests := []struct {
+		name            string
+		requests        int
+		want            bool
+		wantErrorfCount int
+	}{
+		{
+			name:            "met",
+			requests:        2,
+			want:            true,
+			wantErrorfCount: 0,
+		},
+		{
+			name:            "timeout",
+			requests:        1,
+			want:            false,
+			wantErrorfCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock)
+			m.On(http.MethodPost, "https://test.com/webhooks", nil).RespondNoContent().Twice()
+
+			mockT := new(MockTestingT)
+			sent := make(chan struct{})
+			go func() {
+				defer close(sent)
+				for range tt.requests {
+					m.Requested(mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/webhooks", http.NoBody)))
+				}
+			}()
+
+			// Only time out once every request has been sent, so that the
+			// outcome does not depend on scheduling.
+			timeout := 5 * time.Second
+			if !tt.want {
+				<-sent
+				timeout = 0
+			}
+
+			// Test
+			got := m.AssertExpectationsWithin(mockT, timeout)
+
+			// Assertions
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErrorfCount, mockT.errorfCount)
+		})
+	}
+}
+
+func TestMock_OnUnmatched(t *testing.T) {
+	tests := []struct {
+		name       string
+		tolerate   bool
+		want       bool
+		wantErrorf int
+	}{
+		{
+			name:       "fail",
+			tolerate:   false,
+			want:       false,
+			wantErrorf: 1,
+		},
+		{
+			name:       "tolerate",
+			tolerate:   true,
+			want:       true,
+			wantErrorf: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock).Test(t)
+			if tt.tolerate {
+				m.TolerateUnmatched()
+			}
+			m.On(http.MethodGet, "https://test.com/foo", nil).RespondOK([]byte("foo")).Once()
+			fallback := m.OnUnmatched()
+			fallback.Respond(http.StatusTeapot, []byte("fallback"))
+
+			mockT := new(MockTestingT)
+
+			// Test
+			gotMatched := m.Requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody)))
+			gotExhausted := m.Requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody)))
+			gotUnexpected := m.Requested(mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/bar", strings.NewReader(testBody))))
+			got := m.AssertExpectations(mockT)
+
+			// Assertions
+			assert.Same(t, fallback, m.OnUnmatched())
+			assert.Equal(t, []byte("foo"), gotMatched.body)
+			assert.Equal(t, http.StatusTeapot, gotExhausted.statusCode)
+			assert.Equal(t, http.StatusTeapot, gotUnexpected.statusCode)
+
+			assert.Len(t, m.Requests, 3)
+			assert.False(t, m.Requests[0].Unmatched())
+			assert.True(t, m.Requests[1].Unmatched())
+			assert.True(t, m.Requests[2].Unmatched())
+
+			unmatched := m.UnmatchedRequests()
+			if assert.Len(t, unmatched, 2) {
+				assert.Equal(t, []byte(testBody), unmatched[1].Body())
+				assert.Equal(t, http.StatusTeapot, unmatched[1].Response().StatusCode())
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErrorf, mockT.errorfCount)
+			assert.Equal(t, 2, mockT.logfCount)
+		})
+	}
+}
+
+func TestMock_OnUnmatched_OutOfOrder(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	m.OnUnmatched().RespondNoContent()
+	InOrder(
+		m.On(http.MethodPost, "https://test.com/token", nil),
+		m.On(http.MethodGet, "https://test.com/resource", nil),
+	)
+
+	// Test
+	_, err := m.requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/resource", http.NoBody)))
+
+	// Assertions
+	assert.ErrorIs(t, err, ErrOutOfOrder)
+}
+
+func TestMock_ResetExpectations_Unmatched(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	m.OnUnmatched().RespondNoContent()
+
+	// Test
+	m.ResetExpectations()
+	_, err := m.requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/foo", http.NoBody)))
+
+	// Assertions
+	assert.ErrorIs(t, err, ErrUnexpectedRequest)
+}
+
+func TestMock_AssertExpectations_Failures(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	m.On(http.MethodGet, "test.com/foo/1234", nil).RespondOK(nil)
+
+	mockT := new(MockTestingT)
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "test.com/foo/1234", http.NoBody))
+	unexpected := mustNewRequest(http.NewRequest(http.MethodGet, "test.com/bar", http.NoBody))
+
+	m.Requested(received)
+	_, err := m.requested(unexpected)
+	m.recordFailure(err)
+
+	// Test
+	got := m.AssertExpectations(mockT)
+
+	// Assertions
+	assert.ErrorIs(t, err, ErrUnexpectedRequest)
+	assert.False(t, got)
+	assert.Equal(t, 1, mockT.errorfCount)
+	assert.Equal(t, []error{err}, m.Failures())
+}
+
+func TestNewMockT(t *testing.T) {
+	tests := []struct {
+		name            string
+		request         bool
+		wantErrorfCount int
+	}{
+		{
+			name:            "expectations-met",
+			request:         true,
+			wantErrorfCount: 0,
+		},
+		{
+			name:            "expectations-not-met",
+			request:         false,
+			wantErrorfCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			mockTB := new(MockTB)
+			received := mustNewRequest(http.NewRequest(http.MethodGet, "test.com/foo/1234", http.NoBody))
+
+			// Test
+			m := NewMockT(mockTB)
+			m.On(http.MethodGet, "test.com/foo/1234", nil).RespondOK(nil)
+			if tt.request {
+				m.Requested(received)
+			}
+
+			// Assertions
+			assert.Same(t, mockTB, m.test)
+			assert.Len(t, mockTB.cleanups, 1)
+			assert.Zero(t, mockTB.errorfCount)
+
+			mockTB.runCleanups()
+			assert.Equal(t, tt.wantErrorfCount, mockTB.errorfCount)
+		})
+	}
+}
+
 func TestMock_AssertNumberOfRequests_FailToParsePath(t *testing.T) {
 	// Setup
 	mockT := new(MockTestingT)
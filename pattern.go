@@ -0,0 +1,301 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidPattern = errors.New("invalid pattern")
+
+// pathPattern matches the path of a received URL.
+type pathPattern interface {
+	// matchPath reports whether the URL's path matches the pattern, and
+	// returns any named values captured from the path.
+	matchPath(u *url.URL) (map[string]string, bool)
+
+	// String returns the pattern as it was provided.
+	String() string
+}
+
+// hostPattern matches the host of a received URL.
+type hostPattern interface {
+	// matchHost reports whether the host matches the pattern.
+	matchHost(host string) bool
+
+	// String returns the pattern as it was provided.
+	String() string
+}
+
+// templateSegment is a single segment of a [pathTemplate].
+type templateSegment struct {
+	// Literal value of the segment. Only used if name is empty and the
+	// segment is not a wildcard.
+	literal string
+
+	// Name of the wildcard, if the segment is a wildcard.
+	name string
+
+	// Whether or not the segment is a wildcard.
+	wildcard bool
+
+	// Whether or not the wildcard matches the remainder of the path.
+	multi bool
+}
+
+// pathTemplate is a [pathPattern] that uses the same syntax as the path of a
+// [net/http.ServeMux] pattern, such as `/users/{id}` or `/files/{path...}`.
+//
+// Template Logic:
+//   - `{name}` matches exactly one non-empty path segment.
+//   - `{name...}` must be the final segment and matches the remainder of the
+//     path, which may be empty.
+//   - `{$}` must be the final segment and matches only the end of a path that
+//     ends in a slash.
+//   - A template that ends in a slash matches any path with the template as
+//     its prefix.
+type pathTemplate struct {
+	raw string
+
+	segments []templateSegment
+}
+
+// isPathTemplate reports whether a path contains a well-formed wildcard
+// segment, such as `{id}`, `{path...}`, or `{$}`, and so should be parsed as a
+// [pathTemplate]. Braces elsewhere in a path, such as in `/x{y}`, are literal.
+func isPathTemplate(path string) bool {
+	for _, part := range strings.Split(path, "/") {
+		name, ok := strings.CutPrefix(part, "{")
+		if !ok {
+			continue
+		}
+		name, ok = strings.CutSuffix(name, "}")
+		if !ok {
+			continue
+		}
+		if name == "$" || token.IsIdentifier(strings.TrimSuffix(name, "...")) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePathTemplate parses a [pathTemplate] from a path.
+func parsePathTemplate(path string) (*pathTemplate, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("%w: %q: path must begin with '/'", ErrInvalidPattern, path)
+	}
+
+	pt := &pathTemplate{raw: path}
+	names := map[string]bool{}
+
+	parts := strings.Split(path[1:], "/")
+	for i, part := range parts {
+		last := i == len(parts)-1
+
+		if !strings.Contains(part, "{") && !strings.Contains(part, "}") {
+			if last && part == "" && len(parts) > 1 {
+				// Trailing slash matches any path with the template as its
+				// prefix.
+				pt.segments = append(pt.segments, templateSegment{wildcard: true, multi: true})
+				break
+			}
+			pt.segments = append(pt.segments, templateSegment{literal: part})
+			continue
+		}
+
+		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") || strings.Count(part, "{") != 1 || strings.Count(part, "}") != 1 {
+			return nil, fmt.Errorf("%w: %q: wildcard must be an entire path segment", ErrInvalidPattern, path)
+		}
+
+		name := part[1 : len(part)-1]
+		if name == "$" {
+			if !last {
+				return nil, fmt.Errorf("%w: %q: {$} must be the final segment", ErrInvalidPattern, path)
+			}
+			pt.segments = append(pt.segments, templateSegment{literal: ""})
+			break
+		}
+
+		seg := templateSegment{wildcard: true}
+		if n, ok := strings.CutSuffix(name, "..."); ok {
+			if !last {
+				return nil, fmt.Errorf("%w: %q: {%s} must be the final segment", ErrInvalidPattern, path, name)
+			}
+			name = n
+			seg.multi = true
+		}
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("%w: %q: wildcard name %q is not a valid identifier", ErrInvalidPattern, path, name)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("%w: %q: duplicate wildcard name %q", ErrInvalidPattern, path, name)
+		}
+		names[name] = true
+
+		seg.name = name
+		pt.segments = append(pt.segments, seg)
+	}
+
+	return pt, nil
+}
+
+// matchPath matches the escaped path of a URL segment-by-segment, unescaping
+// each segment before comparing it.
+func (pt *pathTemplate) matchPath(u *url.URL) (map[string]string, bool) {
+	path := u.EscapedPath()
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+	parts := strings.Split(path[1:], "/")
+
+	values := map[string]string{}
+	for i, seg := range pt.segments {
+		if seg.multi {
+			rest, err := url.PathUnescape(strings.Join(parts[i:], "/"))
+			if err != nil {
+				return nil, false
+			}
+			if seg.name != "" {
+				values[seg.name] = rest
+			}
+			return values, true
+		}
+
+		if i >= len(parts) {
+			return nil, false
+		}
+		part, err := url.PathUnescape(parts[i])
+		if err != nil {
+			return nil, false
+		}
+
+		if seg.wildcard {
+			if part == "" {
+				return nil, false
+			}
+			values[seg.name] = part
+		} else if part != seg.literal {
+			return nil, false
+		}
+	}
+
+	if len(parts) != len(pt.segments) {
+		return nil, false
+	}
+	return values, true
+}
+
+// String returns the template as it was provided.
+func (pt *pathTemplate) String() string {
+	return pt.raw
+}
+
+// regexpPattern is a [pathPattern] and [hostPattern] that matches a URL
+// component against a regular expression. The expression must match the
+// entire component. Named capture groups are captured as path values.
+type regexpPattern struct {
+	raw string
+
+	re *regexp.Regexp
+}
+
+// newRegexpPattern compiles a [regexpPattern] from a regular expression.
+func newRegexpPattern(expr string) (*regexpPattern, error) {
+	re, err := regexp.Compile(`^(?:` + expr + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidPattern, expr, err)
+	}
+	return &regexpPattern{raw: expr, re: re}, nil
+}
+
+// matchPath matches the unescaped path of a URL against the regular
+// expression.
+func (rp *regexpPattern) matchPath(u *url.URL) (map[string]string, bool) {
+	matches := rp.re.FindStringSubmatch(u.Path)
+	if matches == nil {
+		return nil, false
+	}
+
+	values := map[string]string{}
+	for i, name := range rp.re.SubexpNames() {
+		if name != "" {
+			values[name] = matches[i]
+		}
+	}
+	return values, true
+}
+
+// matchHost matches a host against the regular expression. If the host
+// includes a port and does not match, its hostname is matched instead.
+func (rp *regexpPattern) matchHost(host string) bool {
+	return matchHost(host, rp.re.MatchString)
+}
+
+// String returns the regular expression as it was provided.
+func (rp *regexpPattern) String() string {
+	return rp.raw
+}
+
+// globPattern is a [pathPattern] and [hostPattern] that matches a URL
+// component against a glob, using the syntax of [path.Match]. Wildcards do not
+// match the '/' separator.
+type globPattern struct {
+	raw string
+}
+
+// newGlobPattern validates and creates a [globPattern].
+func newGlobPattern(pattern string) (*globPattern, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidPattern, pattern, err)
+	}
+	return &globPattern{raw: pattern}, nil
+}
+
+// matchPath matches the unescaped path of a URL against the glob.
+func (gp *globPattern) matchPath(u *url.URL) (map[string]string, bool) {
+	ok, _ := path.Match(gp.raw, u.Path)
+	return nil, ok
+}
+
+// matchHost matches a host against the glob. If the host includes a port and
+// does not match, its hostname is matched instead.
+func (gp *globPattern) matchHost(host string) bool {
+	return matchHost(host, func(v string) bool {
+		ok, _ := path.Match(gp.raw, v)
+		return ok
+	})
+}
+
+// String returns the glob as it was provided.
+func (gp *globPattern) String() string {
+	return gp.raw
+}
+
+// matchHost matches a host with the provided function. If the host includes a
+// port and does not match, its hostname is matched instead.
+func matchHost(host string, match func(string) bool) bool {
+	if match(host) {
+		return true
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return match(hostname)
+	}
+	return false
+}
+
+// receivedHost returns the host of a received [http.Request]'s URL. Requests
+// received by a server typically do not include the host in the URL, in which
+// case the request's Host is used instead.
+func receivedHost(received *http.Request) string {
+	if received.URL.Host != "" {
+		return received.URL.Host
+	}
+	return received.Host
+}
@@ -0,0 +1,337 @@
+package httpmock
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parsePathTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+	}{
+		{name: "relative", template: "users/{id}"},
+		{name: "partial-segment", template: "/users/id-{id}"},
+		{name: "unclosed", template: "/users/{id"},
+		{name: "multiple-wildcards", template: "/users/{a}{b}"},
+		{name: "invalid-name", template: "/users/{1d}"},
+		{name: "empty-name", template: "/users/{}"},
+		{name: "duplicate-name", template: "/users/{id}/friends/{id}"},
+		{name: "multi-not-last", template: "/files/{path...}/meta"},
+		{name: "end-not-last", template: "/files/{$}/meta"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got, err := parsePathTemplate(tt.template)
+
+			// Assertions
+			assert.Nil(t, got)
+			assert.ErrorIs(t, err, ErrInvalidPattern)
+		})
+	}
+}
+
+func Test_isPathTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "literal", path: "/users/123", want: false},
+		{name: "wildcard", path: "/users/{id}", want: true},
+		{name: "multi", path: "/files/{path...}", want: true},
+		{name: "end", path: "/files/{$}", want: true},
+		{name: "invalid-wildcard", path: "/users/{id}/{id}", want: true},
+		{name: "partial-segment", path: "/x{y}", want: false},
+		{name: "unclosed", path: "/users/{id", want: false},
+		{name: "invalid-name", path: "/users/{1d}", want: false},
+		{name: "empty-name", path: "/users/{}", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got := isPathTemplate(tt.path)
+
+			// Assertions
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPathTemplate_matchPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		path       string
+		wantValues map[string]string
+		wantOK     bool
+	}{
+		{
+			name:       "single",
+			template:   "/users/{id}",
+			path:       "/users/123",
+			wantValues: map[string]string{"id": "123"},
+			wantOK:     true,
+		},
+		{
+			name:       "multiple",
+			template:   "/users/{id}/friends/{friend}",
+			path:       "/users/123/friends/456",
+			wantValues: map[string]string{"id": "123", "friend": "456"},
+			wantOK:     true,
+		},
+		{
+			name:       "escaped",
+			template:   "/users/{id}",
+			path:       "/users/a%2Fb",
+			wantValues: map[string]string{"id": "a/b"},
+			wantOK:     true,
+		},
+		{
+			name:     "empty-segment",
+			template: "/users/{id}",
+			path:     "/users/",
+		},
+		{
+			name:     "too-long",
+			template: "/users/{id}",
+			path:     "/users/123/friends",
+		},
+		{
+			name:     "too-short",
+			template: "/users/{id}/friends",
+			path:     "/users/123",
+		},
+		{
+			name:     "different-literal",
+			template: "/users/{id}",
+			path:     "/groups/123",
+		},
+		{
+			name:       "remainder",
+			template:   "/files/{path...}",
+			path:       "/files/a/b/c.txt",
+			wantValues: map[string]string{"path": "a/b/c.txt"},
+			wantOK:     true,
+		},
+		{
+			name:       "remainder-empty",
+			template:   "/files/{path...}",
+			path:       "/files/",
+			wantValues: map[string]string{"path": ""},
+			wantOK:     true,
+		},
+		{
+			name:       "trailing-slash-prefix",
+			template:   "/users/{id}/",
+			path:       "/users/123/friends/456",
+			wantValues: map[string]string{"id": "123"},
+			wantOK:     true,
+		},
+		{
+			name:       "end",
+			template:   "/users/{id}/{$}",
+			path:       "/users/123/",
+			wantValues: map[string]string{"id": "123"},
+			wantOK:     true,
+		},
+		{
+			name:     "end-too-long",
+			template: "/users/{id}/{$}",
+			path:     "/users/123/friends",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			pt, err := parsePathTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("unexpected error parsing template: %v", err)
+			}
+			u, err := url.Parse(tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error parsing path: %v", err)
+			}
+
+			// Test
+			gotValues, gotOK := pt.matchPath(u)
+
+			// Assertions
+			assert.Equal(t, tt.wantOK, gotOK)
+			assert.Equal(t, tt.wantValues, gotValues)
+			assert.Equal(t, tt.template, pt.String())
+		})
+	}
+}
+
+func Test_newRegexpPattern_Invalid(t *testing.T) {
+	// Test
+	got, err := newRegexpPattern(`/v[0-9+/health`)
+
+	// Assertions
+	assert.Nil(t, got)
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+}
+
+func Test_newGlobPattern_Invalid(t *testing.T) {
+	// Test
+	got, err := newGlobPattern(`/v[0-9/health`)
+
+	// Assertions
+	assert.Nil(t, got)
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+}
+
+func TestRegexpPattern_matchPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		expr       string
+		path       string
+		wantValues map[string]string
+		wantOK     bool
+	}{
+		{
+			name:       "match",
+			expr:       `/v[0-9]+/health`,
+			path:       "/v12/health",
+			wantValues: map[string]string{},
+			wantOK:     true,
+		},
+		{
+			name:   "anchored",
+			expr:   `/v[0-9]+/health`,
+			path:   "/api/v12/health/check",
+			wantOK: false,
+		},
+		{
+			name:       "named-groups",
+			expr:       `/users/(?P<id>[0-9]+)/(?P<action>[a-z]+)`,
+			path:       "/users/123/edit",
+			wantValues: map[string]string{"id": "123", "action": "edit"},
+			wantOK:     true,
+		},
+		{
+			name:   "mismatch",
+			expr:   `/users/(?P<id>[0-9]+)`,
+			path:   "/users/abc",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			rp, err := newRegexpPattern(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error parsing regexp: %v", err)
+			}
+
+			// Test
+			gotValues, gotOK := rp.matchPath(&url.URL{Path: tt.path})
+
+			// Assertions
+			assert.Equal(t, tt.wantOK, gotOK)
+			assert.Equal(t, tt.wantValues, gotValues)
+			assert.Equal(t, tt.expr, rp.String())
+		})
+	}
+}
+
+func TestGlobPattern_matchPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		wantOK  bool
+	}{
+		{name: "match", pattern: "/v*/health", path: "/v2/health", wantOK: true},
+		{name: "no-separator", pattern: "/v*/health", path: "/v2/api/health", wantOK: false},
+		{name: "character-class", pattern: "/v[0-9]/health", path: "/v2/health", wantOK: true},
+		{name: "mismatch", pattern: "/v[0-9]/health", path: "/va/health", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			gp, err := newGlobPattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("unexpected error parsing glob: %v", err)
+			}
+
+			// Test
+			_, gotOK := gp.matchPath(&url.URL{Path: tt.path})
+
+			// Assertions
+			assert.Equal(t, tt.wantOK, gotOK)
+			assert.Equal(t, tt.pattern, gp.String())
+		})
+	}
+}
+
+func Test_matchHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern func() (hostPattern, error)
+		host    string
+		wantOK  bool
+	}{
+		{
+			name:    "glob",
+			pattern: func() (hostPattern, error) { return newGlobPattern("*.internal") },
+			host:    "api.internal",
+			wantOK:  true,
+		},
+		{
+			name:    "glob-port",
+			pattern: func() (hostPattern, error) { return newGlobPattern("*.internal") },
+			host:    "api.internal:8080",
+			wantOK:  true,
+		},
+		{
+			name:    "glob-with-port",
+			pattern: func() (hostPattern, error) { return newGlobPattern("api.internal:80[0-9][0-9]") },
+			host:    "api.internal:8080",
+			wantOK:  true,
+		},
+		{
+			name:    "glob-mismatch",
+			pattern: func() (hostPattern, error) { return newGlobPattern("*.internal") },
+			host:    "api.external:8080",
+			wantOK:  false,
+		},
+		{
+			name:    "regexp",
+			pattern: func() (hostPattern, error) { return newRegexpPattern(`[a-z]+\.internal`) },
+			host:    "api.internal:8080",
+			wantOK:  true,
+		},
+		{
+			name:    "regexp-mismatch",
+			pattern: func() (hostPattern, error) { return newRegexpPattern(`[a-z]+\.internal`) },
+			host:    "api.internal.example.com",
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			hp, err := tt.pattern()
+			if err != nil {
+				t.Fatalf("unexpected error parsing pattern: %v", err)
+			}
+
+			// Test
+			got := hp.matchHost(tt.host)
+
+			// Assertions
+			assert.Equal(t, tt.wantOK, got)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package httpmock
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+var ErrUpstream = errors.New("error proxying request to upstream")
+
+// hopHeaders are headers that only apply to a single connection, and so are
+// not forwarded by a proxy.
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// removeHopHeaders removes the headers that only apply to a single connection.
+func removeHopHeaders(h http.Header) {
+	for _, key := range hopHeaders {
+		h.Del(key)
+	}
+}
+
+// newUpstreamClient creates the client used to proxy requests to an upstream.
+// It does not follow redirects or decompress responses, so that the upstream's
+// responses are returned to the client and recorded as they were sent.
+func newUpstreamClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableCompression = true
+
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// proxy is a [ResponseWriter] that forwards a received request to the
+// [Server]'s upstream, writes the upstream's response, and records the
+// exchange. If the upstream cannot be reached, a 502 is written and an error
+// wrapping [ErrUpstream] is returned.
+func (s *Server) proxy(w http.ResponseWriter, r *http.Request) (int, error) {
+	body, err := SafeReadBody(r)
+	if err != nil {
+		return 0, s.proxyError(w, err)
+	}
+
+	// Join the paths in their escaped form, so that escaped characters such
+	// as %2F are forwarded as they were received
+	u := *s.upstream
+	u.Path = strings.TrimSuffix(s.upstream.Path, "/") + r.URL.Path
+	u.RawPath = strings.TrimSuffix(s.upstream.EscapedPath(), "/") + r.URL.EscapedPath()
+	u.RawQuery = r.URL.RawQuery
+
+	out, err := http.NewRequestWithContext(r.Context(), r.Method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, s.proxyError(w, err)
+	}
+	out.Header = r.Header.Clone()
+	removeHopHeaders(out.Header)
+
+	resp, err := s.upstreamClient.Do(out)
+	if err != nil {
+		return 0, s.proxyError(w, err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, s.proxyError(w, err)
+	}
+	header := resp.Header.Clone()
+	removeHopHeaders(header)
+
+	s.record(Exchange{
+		Method:         r.Method,
+		URL:            r.URL.RequestURI(),
+		Header:         out.Header,
+		Body:           emptyToNil(body),
+		StatusCode:     resp.StatusCode,
+		ResponseHeader: header,
+		ResponseBody:   emptyToNil(responseBody),
+	})
+
+	h := w.Header()
+	for key, values := range header {
+		h[key] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	n, err := w.Write(responseBody)
+	if err != nil {
+		return n, ErrWriteReturnBody
+	}
+	return n, nil
+}
+
+// emptyToNil returns nil for an empty body, so that empty bodies are recorded
+// the same way as they are loaded from a fixture.
+func emptyToNil(body []byte) []byte {
+	if len(body) == 0 {
+		return nil
+	}
+	return body
+}
+
+// proxyError writes a 502 for a request that could not be proxied to the
+// upstream, and returns an error wrapping [ErrUpstream].
+func (s *Server) proxyError(w http.ResponseWriter, err error) error {
+	err = fmt.Errorf("%w %s: %w", ErrUpstream, s.upstream, err)
+	http.Error(w, err.Error(), http.StatusBadGateway)
+	return err
+}
+
+// record adds an exchange to the [Server]'s recorded exchanges.
+func (s *Server) record(e Exchange) {
+	s.Mock.mutex.Lock()
+	defer s.Mock.mutex.Unlock()
+
+	s.exchanges = append(s.exchanges, e)
+}
+
+// Exchanges returns a snapshot of the exchanges that have been proxied to the
+// [Server]'s upstream and recorded, in the order they were received.
+func (s *Server) Exchanges() []Exchange {
+	s.Mock.mutex.Lock()
+	defer s.Mock.mutex.Unlock()
+
+	return slices.Clone(s.exchanges)
+}
+
+// WriteFixture writes the exchanges that have been proxied to the [Server]'s
+// upstream and recorded to a fixture, which may be replayed with
+// [Mock.LoadFixture].
+func (s *Server) WriteFixture(w io.Writer) error {
+	return writeFixture(w, s.Exchanges())
+}
+
+// writeRecordFile writes the recorded exchanges to the [Server]'s record file,
+// if one was configured.
+func (s *Server) writeRecordFile() error {
+	if s.recordFile == "" {
+		return nil
+	}
+
+	f, err := os.Create(s.recordFile)
+	if err != nil {
+		return err
+	}
+	if err := s.WriteFixture(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// setUpstream configures the [Server] to proxy requests that do not match an
+// expectation to an upstream, by setting a passthrough fallback expectation
+// with [Mock.OnUnmatched]. Proxied requests are tolerated by
+// [Mock.AssertExpectations], and the fallback expectation is kept when the
+// [Mock]'s expectations are reset.
+func (s *Server) setUpstream(upstream string) {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		s.Mock.fail("failed to parse upstream url. Error: %v\n", err)
+		return
+	}
+	if u.Scheme == "" || u.Host == "" {
+		s.Mock.fail("failed to parse upstream url. Error: %q must include a scheme and host\n", upstream)
+		return
+	}
+
+	s.upstream = u
+	s.upstreamClient = newUpstreamClient()
+	s.Mock.TolerateUnmatched()
+	s.Mock.OnUnmatched().RespondUsing(s.proxy)
+
+	s.Mock.mutex.Lock()
+	s.Mock.keepFallback = true
+	s.Mock.mutex.Unlock()
+}
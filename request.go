@@ -2,14 +2,18 @@ package httpmock
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
-	"reflect"
-	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -28,14 +32,35 @@ var (
 
 	fmtAnyBody  = "(AnyBody)"
 	fmtMissing  = "(Missing)"
+	fmtPresent  = "(Present)"
 	fmtNotEqual = "!="
 	fmtEqual    = "=="
+
+	msgModifySnapshot = "httpmock: a snapshot of a request or response cannot be modified"
 )
 
 // RequestMatcher is used by the [Request.Matches] method to match a
 // [http.Request].
 type RequestMatcher func(received *http.Request) (output string, differences int)
 
+// SequencePolicy determines which response is returned by a [Request] with a
+// sequence of responses, after every response in the sequence has been
+// returned.
+type SequencePolicy int
+
+const (
+	// SequenceRepeatLast returns the last response in the sequence for every
+	// request after the sequence has been returned. This is the default.
+	SequenceRepeatLast SequencePolicy = iota
+
+	// SequenceCycle starts over at the first response in the sequence.
+	SequenceCycle
+
+	// SequenceFail treats any request after the sequence has been returned as
+	// unexpected.
+	SequenceFail
+)
+
 // Request represents a [http.Request] and is used for setting expectations,
 // as well as recording activity.
 type Request struct {
@@ -48,22 +73,100 @@ type Request struct {
 	// fragment.
 	url *url.URL
 
+	// Pattern that will be used to match the path of a received request
+	// instead of exact comparison.
+	pathPattern pathPattern
+
+	// Pattern that will be used to match the host of a received request
+	// instead of exact comparison.
+	hostPattern hostPattern
+
 	// The body that was or will be requested.
 	body []byte
 
-	// List of RequestMatcher functions to run against any received request.
-	matchers []RequestMatcher
+	// Whether or not the body should be compared as JSON, ignoring object key
+	// order and whitespace.
+	jsonBody bool
+
+	// Whether or not a received JSON body may contain object fields that are
+	// not found in the expected JSON body.
+	allowExtraFields bool
+
+	// Matches a received JSON body by decoding it into a Go value, instead of
+	// comparing it to the expected body.
+	typedBody *typedJSONBody
+
+	// List of matcher functions to run against any received request.
+	matchers []requestMatcher
 
 	// Holds the parts of the response that should be returned when setting
 	// this request is received.
 	response *Response
 
+	// Responses that should be returned, in order, after the first response.
+	sequence []*Response
+
+	// Determines which response is returned after the sequence has been
+	// returned.
+	sequencePolicy SequencePolicy
+
 	// The number of times to return the response when setting expectations.
 	// 0 means to always return the value.
 	repeatability int
 
+	// Whether or not repeatability is an upper bound rather than an exact
+	// count, with minRequests as the lower bound.
+	countRange bool
+
+	// Minimum number of times this request must be received for expectations
+	// to be met. Only used if countRange is set.
+	minRequests int
+
+	// Whether or not expectations are met if this request is never received.
+	optional bool
+
 	// Amount of times this request has been received.
 	totalRequests int
+
+	// Expectations that must be satisfied before this request may be matched.
+	prerequisites []*Request
+
+	// Functions and channels that are invoked when this request is matched,
+	// before its response is written.
+	hooks requestHooks
+
+	// Name of the scenario that this request belongs to, the state the
+	// scenario must be in for this request to be matched, and the state the
+	// scenario moves to when this request is matched.
+	scenario      string
+	requiredState string
+	newState      string
+
+	// Metadata of a received request. These fields are only populated for
+	// requests recorded in [Mock.Requests].
+	header        http.Header
+	host          string
+	proto         string
+	remoteAddr    string
+	tls           *tls.ConnectionState
+	contentLength int64
+	trailer       http.Header
+	receivedAt    time.Time
+
+	// Values captured by the path pattern of the matched expectation. Only
+	// populated for requests recorded in [Mock.Requests].
+	pathValues map[string]string
+
+	// Sequence number of a received request, and the response that was
+	// written for it, if it was captured by the default [Server] handler or
+	// [Transport]. Only populated for requests recorded in [Mock.Requests].
+	seq     int
+	written *writtenResponse
+
+	// Whether or not a received request was handled by the fallback
+	// expectation set with [Mock.OnUnmatched]. Only populated for requests
+	// recorded in [Mock.Requests].
+	unmatched bool
 }
 
 func newRequest(parent *Mock, method string, URL *url.URL, body []byte) *Request {
@@ -75,8 +178,236 @@ func newRequest(parent *Mock, method string, URL *url.URL, body []byte) *Request
 	}
 }
 
-// lock is a convenience method to lock the parent [Mock]'s mutex.
+// newReceivedRequest creates a [Request] that records a received
+// [http.Request], including its metadata. The body must already have been
+// read, as the [http.Request]'s trailers are only available afterward.
+func newReceivedRequest(parent *Mock, received *http.Request, body []byte) *Request {
+	r := newRequest(parent, received.Method, received.URL, body)
+	r.header = received.Header.Clone()
+	r.host = received.Host
+	r.proto = received.Proto
+	r.remoteAddr = received.RemoteAddr
+	r.contentLength = received.ContentLength
+	r.trailer = received.Trailer.Clone()
+	r.receivedAt = time.Now()
+	if received.TLS != nil {
+		state := *received.TLS
+		r.tls = &state
+	}
+	return r
+}
+
+// snapshot creates a copy of a [Request] that may be safely inspected without
+// holding the parent [Mock]'s mutex. The copy is detached from the [Mock], so
+// its methods that modify an expectation panic.
+func (r *Request) snapshot() Request {
+	c := new(Request)
+	*c = *r
+	c.parent = nil
+	c.prerequisites = nil
+	c.hooks = requestHooks{}
+	if r.url != nil {
+		u := *r.url
+		c.url = &u
+	}
+	if r.body != nil {
+		c.body = append([]byte{}, r.body...)
+	}
+	if r.matchers != nil {
+		c.matchers = append([]requestMatcher{}, r.matchers...)
+	}
+	if r.response != nil {
+		c.response = r.response.clone()
+		c.response.parent = c
+	}
+	if r.sequence != nil {
+		c.sequence = make([]*Response, len(r.sequence))
+		for i, resp := range r.sequence {
+			c.sequence[i] = resp.clone()
+			c.sequence[i].parent = c
+		}
+	}
+	c.header = r.header.Clone()
+	c.trailer = r.trailer.Clone()
+	c.pathValues = maps.Clone(r.pathValues)
+	return *c
+}
+
+// Method returns the HTTP method that was or will be requested.
+//
+// Note: The getters on [Request] do not lock the parent [Mock]. To inspect
+// requests while the [Mock] is in use, use the snapshots returned by methods
+// such as [Mock.Calls] and [Mock.Expectations].
+func (r *Request) Method() string {
+	return r.method
+}
+
+// URL returns a copy of the URL that was or will be requested.
+func (r *Request) URL() *url.URL {
+	if r.url == nil {
+		return nil
+	}
+	u := *r.url
+	return &u
+}
+
+// Body returns a copy of the body that was or will be requested.
+func (r *Request) Body() []byte {
+	if r.body == nil {
+		return nil
+	}
+	return append([]byte{}, r.body...)
+}
+
+// Response returns a copy of the response that will be returned for an
+// expected request, or that was returned for a received request. It returns
+// nil if no response is configured. The copy is detached from the [Mock], so
+// its methods that modify the response panic.
+func (r *Request) Response() *Response {
+	if r.response == nil {
+		return nil
+	}
+	c := r.snapshot()
+	return c.response
+}
+
+// Responses returns copies of the responses that are returned, in order, when
+// the request is received. It includes the first response and any responses
+// added with [Response.ThenRespond]. Like [Request.Response], the copies are
+// detached from the [Mock].
+func (r *Request) Responses() []*Response {
+	if r.response == nil {
+		return nil
+	}
+	c := r.snapshot()
+	return append([]*Response{c.response}, c.sequence...)
+}
+
+// TotalRequests returns the number of times an expected request has been
+// received.
+func (r *Request) TotalRequests() int {
+	return r.totalRequests
+}
+
+// Header returns the headers of a received request. It returns nil for
+// expected requests.
+func (r *Request) Header() http.Header {
+	return r.header.Clone()
+}
+
+// Host returns the host on which a received request was sought, as reported
+// by [http.Request.Host].
+func (r *Request) Host() string {
+	return r.host
+}
+
+// Proto returns the protocol version of a received request, such as
+// "HTTP/1.1".
+func (r *Request) Proto() string {
+	return r.proto
+}
+
+// RemoteAddr returns the network address that sent a received request.
+func (r *Request) RemoteAddr() string {
+	return r.remoteAddr
+}
+
+// TLS returns the TLS connection state of a received request, or nil if the
+// request was not received over TLS.
+func (r *Request) TLS() *tls.ConnectionState {
+	return r.tls
+}
+
+// ContentLength returns the content length reported by a received request.
+// The value -1 indicates that the length is unknown.
+func (r *Request) ContentLength() int64 {
+	return r.contentLength
+}
+
+// Trailer returns the trailers of a received request.
+func (r *Request) Trailer() http.Header {
+	return r.trailer.Clone()
+}
+
+// ReceivedAt returns the time at which a received request was recorded.
+func (r *Request) ReceivedAt() time.Time {
+	return r.receivedAt
+}
+
+// PathValue returns the value captured for the named path wildcard when a
+// received request was matched against an expectation with a templated path.
+// It returns the empty string if there is no such value.
+func (r *Request) PathValue(name string) string {
+	return r.pathValues[name]
+}
+
+// Unmatched returns whether a received request was handled by the fallback
+// expectation set with [Mock.OnUnmatched], rather than matching an expectation.
+func (r *Request) Unmatched() bool {
+	return r.unmatched
+}
+
+// setPathValues sets the values captured by the [Request]'s path pattern on a
+// received [http.Request], so that they are available via
+// [http.Request.PathValue]. It returns the captured values.
+func (r *Request) setPathValues(received *http.Request) map[string]string {
+	if r.pathPattern == nil || received.URL == nil {
+		return nil
+	}
+
+	values, ok := r.pathPattern.matchPath(received.URL)
+	if !ok {
+		return nil
+	}
+	for name, value := range values {
+		received.SetPathValue(name, value)
+	}
+	return values
+}
+
+// urlString formats the [Request]'s URL, using the host and path patterns in
+// place of the host and path if they are configured.
+func (r *Request) urlString() string {
+	if r.pathPattern == nil && r.hostPattern == nil {
+		return r.url.String()
+	}
+
+	host := r.url.Host
+	if r.hostPattern != nil {
+		host = r.hostPattern.String()
+	}
+	path := r.url.EscapedPath()
+	if r.pathPattern != nil {
+		path = r.pathPattern.String()
+	}
+
+	var sb strings.Builder
+	if r.url.Scheme != "" {
+		sb.WriteString(r.url.Scheme)
+		sb.WriteString(":")
+	}
+	if r.url.Scheme != "" || host != "" {
+		sb.WriteString("//")
+		sb.WriteString(host)
+	}
+	sb.WriteString(path)
+	if r.url.RawQuery != "" {
+		sb.WriteString("?")
+		sb.WriteString(r.url.RawQuery)
+	}
+	if r.url.Fragment != "" {
+		sb.WriteString("#")
+		sb.WriteString(r.url.EscapedFragment())
+	}
+	return sb.String()
+}
+
+// lock is a convenience method to lock the parent [Mock]'s mutex. It panics if
+// the [Request] is a snapshot, which has no parent [Mock] to modify.
 func (r *Request) lock() {
+	if r.parent == nil {
+		panic(msgModifySnapshot)
+	}
 	r.parent.mutex.Lock()
 }
 
@@ -111,6 +442,43 @@ func (r *Request) RespondOK(body []byte) *Response {
 	return r.Respond(http.StatusOK, body)
 }
 
+// RespondJSON sets the response for the [Request] to a status code and a body
+// marshaled from the provided value as JSON, with a Content-Type of
+// application/json. The test fails if the value cannot be marshaled.
+//
+//	Mock.On(http.MethodGet, "/users/1234", nil).RespondJSON(http.StatusOK, User{ID: "1234"})
+func (r *Request) RespondJSON(statusCode int, v any) *Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		r.parent.fail("failed to marshal JSON response body. Error: %v\n", err)
+	}
+	return r.Respond(statusCode, body).Header("Content-Type", "application/json")
+}
+
+// RespondTemplate sets the response for the [Request] to a status code and a
+// body rendered from a [text/template] for each received request. The template
+// is executed with [TemplateData] describing the received request, and may use
+// the uuid, now, counter, and json functions. The test fails if the template
+// cannot be parsed. If it cannot be rendered, [Response.Write] returns an error
+// wrapping [ErrRenderTemplate].
+//
+//	Mock.On(http.MethodPost, "/orders", AnyBody).
+//		RespondTemplate(http.StatusCreated, `{"id": {{json .JSON.id}}, "created": "{{now.Format "2006-01-02"}}"}`)
+func (r *Request) RespondTemplate(statusCode int, text string) *Response {
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		r.parent.fail("failed to parse response template. Error: %v\n", err)
+	}
+
+	resp := r.Respond(statusCode, nil)
+
+	r.lock()
+	defer r.unlock()
+
+	resp.template = tmpl
+	return resp
+}
+
 // RespondNoContent is a convenience method that sets the status code as 204.
 //
 //	Mock.On(http.MethodDelete, "/some/path/1234").RespondNoContent()
@@ -118,6 +486,14 @@ func (r *Request) RespondNoContent() *Response {
 	return r.Respond(http.StatusNoContent, nil)
 }
 
+// RespondFault is a convenience method that sets a [Fault] to simulate
+// instead of writing a well-formed response.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).RespondFault(FaultCloseConnection)
+func (r *Request) RespondFault(fault Fault) *Response {
+	return r.Respond(http.StatusOK, nil).Fault(fault)
+}
+
 // RespondUsing overrides the [Request.Respond] functionality by allowing a
 // custom writer to be invoked instead of the typical writing functionality.
 //
@@ -137,6 +513,289 @@ func (r *Request) RespondUsing(writer ResponseWriter) *Response {
 	return resp
 }
 
+// AfterSequence sets the [SequencePolicy] that determines which response is
+// returned after every response added with [Response.ThenRespond] has been
+// returned.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).
+//		Respond(http.StatusServiceUnavailable, nil).
+//		ThenRespondOK([]byte(`{"foo": "bar"}`)).
+//		AfterSequence(SequenceFail)
+func (r *Request) AfterSequence(policy SequencePolicy) *Request {
+	r.lock()
+	defer r.unlock()
+
+	r.sequencePolicy = policy
+	return r
+}
+
+// sequenceLength returns the number of responses in the [Request]'s sequence
+// of responses, or 0 if the [Request] does not have a sequence.
+func (r *Request) sequenceLength() int {
+	if len(r.sequence) == 0 {
+		return 0
+	}
+	return len(r.sequence) + 1
+}
+
+// validateSequenceCount fails the test if the [Request] may be received fewer
+// times than there are responses in its sequence of responses, as it could
+// then never be satisfied. The parent [Mock]'s mutex must not be held by the
+// caller.
+func (r *Request) validateSequenceCount() {
+	r.lock()
+	n := r.sequenceLength()
+	maximum := r.repeatability
+	r.unlock()
+
+	if maximum > 0 && maximum < n {
+		r.parent.fail("failed to configure request: at most %d requests are expected, but the sequence has %d responses, so the expectation can never be satisfied\n", maximum, n)
+	}
+}
+
+// nextResponse returns the response that should be returned for the next
+// received request, based on the number of requests already received and the
+// [SequencePolicy].
+func (r *Request) nextResponse() *Response {
+	n := r.sequenceLength()
+	if n == 0 {
+		return r.response
+	}
+
+	i := r.totalRequests
+	if r.sequencePolicy == SequenceCycle {
+		i %= n
+	} else {
+		i = min(i, n-1)
+	}
+
+	if i == 0 {
+		return r.response
+	}
+	return r.sequence[i-1]
+}
+
+// exhausted reports whether the [Request] may no longer be matched, either
+// because it has been received the number of times set with [Request.Times],
+// or because its sequence of responses has been returned and the
+// [SequencePolicy] is [SequenceFail].
+func (r *Request) exhausted() bool {
+	if r.repeatability == -1 {
+		return true
+	}
+	return r.sequencePolicy == SequenceFail && r.sequenceLength() > 0 && r.totalRequests >= r.sequenceLength()
+}
+
+// satisfied reports whether the [Request] has been received as many times as
+// expected, including every response in its sequence of responses. A
+// [Request] marked with [Request.Maybe] is satisfied if it was never received,
+// and does not need every response in its sequence of responses to have been
+// returned.
+func (r *Request) satisfied() bool {
+	if r.optional && r.totalRequests == 0 {
+		return true
+	}
+	if !r.optional && r.totalRequests < r.sequenceLength() {
+		return false
+	}
+	if r.countRange {
+		return r.totalRequests >= r.minRequests
+	}
+	return r.totalRequests > 0 && r.repeatability <= 0
+}
+
+// After indicates that the [Request] may only be matched once every one of the
+// provided requests has been satisfied. The requests must belong to the same
+// [Mock].
+//
+//	token := Mock.On(http.MethodPost, "/token", nil).RespondOK(token).Once()
+//	Mock.On(http.MethodGet, "/resource", nil).RespondOK(resource).After(token)
+func (r *Request) After(others ...*Request) *Request {
+	r.lock()
+	defer r.unlock()
+
+	r.prerequisites = append(r.prerequisites, others...)
+	return r
+}
+
+// InOrder indicates that each of the provided requests may only be matched
+// once the request before it has been satisfied.
+//
+//	InOrder(
+//		Mock.On(http.MethodPost, "/token", nil).Once(),
+//		Mock.On(http.MethodGet, "/resource", nil).Once(),
+//	)
+func InOrder(requests ...*Request) {
+	for i := 1; i < len(requests); i++ {
+		requests[i].After(requests[i-1])
+	}
+}
+
+// unsatisfiedPrerequisite returns the first expectation that must be satisfied
+// before the [Request] may be matched, or nil if the [Request] may be matched.
+// If the parent [Mock] is in strict order mode, the expectation registered
+// before the [Request] is also a prerequisite. The parent [Mock]'s mutex must be
+// held by the caller.
+func (r *Request) unsatisfiedPrerequisite() *Request {
+	for _, prerequisite := range r.prerequisites {
+		if !prerequisite.satisfied() {
+			return prerequisite
+		}
+	}
+
+	if r.parent != nil && r.parent.strictOrder {
+		for i, er := range r.parent.ExpectedRequests {
+			if er == r {
+				if i > 0 && !r.parent.ExpectedRequests[i-1].satisfied() {
+					return r.parent.ExpectedRequests[i-1]
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Unset removes the [Request] from the expectations of the parent [Mock], along
+// with any ordering constraints that depend on it. If the [Request] is the
+// fallback expectation set with [Mock.OnUnmatched], the fallback is removed
+// instead. Requests that were already received are kept. Unset has no effect
+// if the [Request] was already removed. It is safe to call while a [Server] is
+// serving requests.
+//
+//	r := Mock.On(http.MethodGet, "/some/path", nil).RespondOK(nil).Once()
+//	r.Unset()
+func (r *Request) Unset() *Request {
+	r.lock()
+	defer r.unlock()
+
+	if r.parent.fallback == r {
+		r.parent.fallback = nil
+	}
+
+	expectations := make([]*Request, 0, len(r.parent.ExpectedRequests))
+	for _, er := range r.parent.ExpectedRequests {
+		if er == r {
+			continue
+		}
+		if slices.Contains(er.prerequisites, r) {
+			er.prerequisites = slices.DeleteFunc(slices.Clone(er.prerequisites), func(p *Request) bool { return p == r })
+		}
+		expectations = append(expectations, er)
+	}
+	r.parent.ExpectedRequests = expectations
+	r.parent.notifyChanged()
+	return r
+}
+
+// WaitUntilCalled blocks until the [Request] has been received as many times
+// as expected, or the timeout elapses. It returns whether the [Request] was
+// received as expected.
+//
+//	r := Mock.On(http.MethodPost, "/webhooks", nil).RespondNoContent().Once()
+//	assert.True(t, r.WaitUntilCalled(time.Second))
+func (r *Request) WaitUntilCalled(timeout time.Duration) bool {
+	if r.parent == nil {
+		panic(msgModifySnapshot)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return r.parent.waitUntil(ctx, r.satisfied)
+}
+
+// requestHooks holds the functions and channels that are invoked when a
+// [Request] is matched, before its response is written.
+type requestHooks struct {
+	// Function to call with the received request.
+	run func(*http.Request)
+
+	// Channel to send to once the received request has been matched.
+	notify chan<- struct{}
+
+	// Channel to receive from before the response is returned.
+	wait <-chan time.Time
+}
+
+// invoke calls the run function, notifies the notify channel, and then waits
+// for the wait channel. The received request's body is reset after the run
+// function is called, so that it may be read again by the response. If the
+// received request's context is done while notifying or waiting, an error
+// wrapping [ErrResponseCanceled] is returned.
+func (h requestHooks) invoke(received *http.Request, receivedBody []byte) error {
+	if h.run != nil {
+		h.run(received)
+		received.Body = io.NopCloser(bytes.NewBuffer(receivedBody))
+	}
+
+	ctx := received.Context()
+	if h.notify != nil {
+		select {
+		case h.notify <- struct{}{}:
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrResponseCanceled, ctx.Err())
+		}
+	}
+	if h.wait != nil {
+		select {
+		case <-h.wait:
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrResponseCanceled, ctx.Err())
+		}
+	}
+	return nil
+}
+
+// Run sets a function that is called with the received [http.Request] each
+// time the [Request] is matched, before the response is returned. The
+// request's body may be read, as it is reset afterward. Only the most
+// recently set function is called.
+//
+//	Mock.On(http.MethodPost, "/some/path", AnyBody).Run(func(r *http.Request) {
+//		got = r.Header.Get("X-Request-Id")
+//	})
+func (r *Request) Run(fn func(*http.Request)) *Request {
+	r.lock()
+	defer r.unlock()
+
+	r.hooks.run = fn
+	return r
+}
+
+// WaitUntil pauses the response each time the [Request] is matched, until the
+// provided channel receives a value or is closed. The response is abandoned if
+// the received request's context is done first.
+//
+//	release := make(chan time.Time)
+//	Mock.On(http.MethodGet, "/some/path", nil).RespondOK(nil).WaitUntil(release)
+//	// ...
+//	close(release)
+func (r *Request) WaitUntil(w <-chan time.Time) *Request {
+	r.lock()
+	defer r.unlock()
+
+	r.hooks.wait = w
+	return r
+}
+
+// NotifyOn sends a value to the provided channel each time the [Request] is
+// matched, after the function set with [Request.Run] is called and before
+// waiting for the channel set with [Request.WaitUntil]. The send blocks until
+// the value is received, or the received request's context is done.
+//
+//	arrived := make(chan struct{})
+//	Mock.On(http.MethodGet, "/some/path", nil).RespondOK(nil).NotifyOn(arrived)
+//	// ...
+//	<-arrived
+func (r *Request) NotifyOn(ch chan<- struct{}) *Request {
+	r.lock()
+	defer r.unlock()
+
+	r.hooks.notify = ch
+	return r
+}
+
 // Once indicates that the [Mock] should only return the response once.
 //
 //	Mock.On(http.MethodDelete, "/some/path/1234").Once()
@@ -152,20 +811,178 @@ func (r *Request) Twice() *Request {
 }
 
 // Times indicates that the [Mock] should only return the indicated number
-// of times.
+// of times. The test fails if that is fewer than the number of responses in
+// the sequence of responses, as the [Request] could never be satisfied.
 //
 //	Mock.On(http.MethodDelete, "/some/path/1234").Times(5)
 func (r *Request) Times(i int) *Request {
 	r.lock()
+	defer r.validateSequenceCount()
 	defer r.unlock()
 
 	r.repeatability = i
+	r.countRange = false
+	r.minRequests = 0
+	return r
+}
+
+// Maybe indicates that the [Request] is allowed, but not required, to be
+// received. [Mock.AssertExpectations] does not fail if it was never received,
+// or if only some of its sequence of responses were returned.
+//
+//	Mock.On(http.MethodGet, "/health", nil).RespondOK(nil).Maybe()
+func (r *Request) Maybe() *Request {
+	r.lock()
+	defer r.unlock()
+
+	r.optional = true
+	return r
+}
+
+// AtLeast indicates that the [Mock] should expect the request at least the
+// indicated number of times, and return the response every time.
+//
+//	Mock.On(http.MethodGet, "/jobs/1234", nil).AtLeast(2)
+func (r *Request) AtLeast(i int) *Request {
+	return r.Between(i, 0)
+}
+
+// AtMost indicates that the [Mock] should expect the request at most the
+// indicated number of times, including not at all.
+//
+//	Mock.On(http.MethodGet, "/jobs/1234", nil).AtMost(3)
+func (r *Request) AtMost(i int) *Request {
+	r.lock()
+	defer r.validateSequenceCount()
+	defer r.unlock()
+
+	r.setCountRange(0, max(i, 0))
+	if i <= 0 {
+		r.repeatability = -1
+	}
+	return r
+}
+
+// Between indicates that the [Mock] should expect the request at least minimum
+// and at most maximum number of times. A maximum of 0 means that there is no
+// upper bound.
+//
+//	Mock.On(http.MethodGet, "/jobs/1234", nil).Between(2, 5)
+func (r *Request) Between(minimum int, maximum int) *Request {
+	r.lock()
+	defer r.validateSequenceCount()
+	defer r.unlock()
+
+	r.setCountRange(minimum, maximum)
+	return r
+}
+
+// setCountRange sets the lower and upper bounds of the number of times the
+// request is expected. An upper bound of 0 means that there is no upper bound.
+// The parent [Mock]'s mutex must be held by the caller.
+func (r *Request) setCountRange(minimum int, maximum int) {
+	minimum = max(minimum, 0)
+	if maximum > 0 && maximum < minimum {
+		minimum, maximum = maximum, minimum
+	}
+
+	r.countRange = true
+	r.minRequests = minimum
+	r.repeatability = max(maximum, 0)
+}
+
+// AllowExtraFields indicates that a received JSON body may contain object
+// fields that are not found in the expected JSON body. It only applies to
+// requests configured with [Mock.OnJSON].
+//
+//	Mock.OnJSON(http.MethodPost, "/some/path", []byte(`{"name": "foo"}`)).AllowExtraFields()
+func (r *Request) AllowExtraFields() *Request {
+	r.lock()
+	defer r.unlock()
+
+	r.allowExtraFields = true
+	return r
+}
+
+// PathRegexp indicates that the path of a received request should match the
+// provided regular expression instead of the expected URL's path. The
+// expression must match the entire path. Named capture groups are available
+// via [http.Request.PathValue].
+//
+//	Mock.On(http.MethodGet, "/", nil).PathRegexp(`/v[0-9]+/health`)
+func (r *Request) PathRegexp(expr string) *Request {
+	rp, err := newRegexpPattern(expr)
+	if err != nil {
+		r.parent.fail("failed to parse path regexp. Error: %v\n", err)
+	}
+
+	r.lock()
+	defer r.unlock()
+
+	r.pathPattern = rp
+	return r
+}
+
+// PathGlob indicates that the path of a received request should match the
+// provided glob instead of the expected URL's path. The glob uses the syntax of
+// [path.Match].
+//
+//	Mock.On(http.MethodGet, "/", nil).PathGlob("/v*/health")
+func (r *Request) PathGlob(pattern string) *Request {
+	gp, err := newGlobPattern(pattern)
+	if err != nil {
+		r.parent.fail("failed to parse path glob. Error: %v\n", err)
+	}
+
+	r.lock()
+	defer r.unlock()
+
+	r.pathPattern = gp
+	return r
+}
+
+// HostRegexp indicates that the host of a received request should match the
+// provided regular expression instead of the expected URL's host. The
+// expression must match the entire host, or the hostname if the host includes
+// a port.
+//
+//	Mock.On(http.MethodGet, "/health", nil).HostRegexp(`[a-z]+\.internal`)
+func (r *Request) HostRegexp(expr string) *Request {
+	rp, err := newRegexpPattern(expr)
+	if err != nil {
+		r.parent.fail("failed to parse host regexp. Error: %v\n", err)
+	}
+
+	r.lock()
+	defer r.unlock()
+
+	r.hostPattern = rp
+	return r
+}
+
+// HostGlob indicates that the host of a received request should match the
+// provided glob instead of the expected URL's host. The glob uses the syntax
+// of [path.Match], and must match the entire host, or the hostname if the host
+// includes a port.
+//
+//	Mock.On(http.MethodGet, "/health", nil).HostGlob("*.internal")
+func (r *Request) HostGlob(pattern string) *Request {
+	gp, err := newGlobPattern(pattern)
+	if err != nil {
+		r.parent.fail("failed to parse host glob. Error: %v\n", err)
+	}
+
+	r.lock()
+	defer r.unlock()
+
+	r.hostPattern = gp
 	return r
 }
 
-// Matches adds one or more [RequestMatcher]'s to the Request.
-// [RequestMatcher]'s are called in FIFO order after the HTTP method, URL, and
-// body have been matched.
+// Matches adds one or more matchers to the Request. Each matcher must be a
+// [ResultMatcher], such as [HeaderEquals], or a [RequestMatcher], which is
+// converted to a [ResultMatcher] with [AdaptMatcher]. Matchers are called in
+// FIFO order after the HTTP method, URL, and body have been matched.
 //
 //	func queryAtLeast(key string, minValue int) RequestMatcher {
 //		fn := func(received *http.Request) (output string, differences int) {
@@ -194,11 +1011,28 @@ func (r *Request) Times(i int) *Request {
 //	}
 //
 //	Mock.On(http.MethodGet, "/some/path/1234", nil).Matches(queryAtLeast("page", 2))
-func (r *Request) Matches(matchers ...RequestMatcher) *Request {
+func (r *Request) Matches(matchers ...any) *Request {
+	converted := make([]requestMatcher, 0, len(matchers))
+	for _, fn := range matchers {
+		switch fn := fn.(type) {
+		case ResultMatcher:
+			converted = append(converted, newResultMatcher(fn))
+		case func(*http.Request) MatchResult:
+			converted = append(converted, newResultMatcher(fn))
+		case RequestMatcher:
+			converted = append(converted, newRequestMatcher(fn))
+		case func(*http.Request) (string, int):
+			converted = append(converted, newRequestMatcher(fn))
+		default:
+			r.parent.fail("unsupported matcher type %T, expected a ResultMatcher or RequestMatcher\n", fn)
+			return r
+		}
+	}
+
 	r.lock()
 	defer r.unlock()
 
-	r.matchers = append(r.matchers, matchers...)
+	r.matchers = append(r.matchers, converted...)
 	return r
 }
 
@@ -225,13 +1059,9 @@ func diffMissing(v string) (string, bool) {
 	return v, true
 }
 
-// diffMethod detects differences between a [Request]'s HTTP method and a
-// [http.Request]'s HTTP method. It responds with a formatted string of the
-// difference and the calculated number of differences.
-func (r *Request) diffMethod(received *http.Request) (string, int) {
-	var output string
-	var differences int
-
+// matchMethod detects differences between a [Request]'s HTTP method and a
+// [http.Request]'s HTTP method.
+func (r *Request) matchMethod(received *http.Request) MatchResult {
 	expected := r.method
 	if r.method == "" {
 		expected = fmtMissing
@@ -244,31 +1074,22 @@ func (r *Request) diffMethod(received *http.Request) (string, int) {
 		actual = fmtMissing
 	}
 
-	if (r.method == AnyMethod && received.Method != "") || ((r.method == received.Method) && (r.method != "")) {
-		output = fmt.Sprintf("\t%d: PASS:  %s == %s\n", 0, actual, expected)
-	} else {
-		output = fmt.Sprintf("\t%d: FAIL:  %s != %s\n", 0, actual, expected)
-		differences++
-	}
-
-	return output, differences
+	pass := (r.method == AnyMethod && received.Method != "") || ((r.method == received.Method) && (r.method != ""))
+	return newMatchResult("Method", actual, expected, pass)
 }
 
-// diffQuery detects differences between a [Request]'s query parameters and a
-// [http.Request]'s query parameters. It responds with a formatted string of the
-// differences and the calculated number of differences.
+// matchQuery detects differences between a [Request]'s query parameters and a
+// [http.Request]'s query parameters. It responds with false if the [Request]
+// has no query parameters to compare.
 //
 // Query Logic:
 //   - If [Request] query is empty, don't compare query parameters at all.
 //   - Otherwise, only compare query parameters found in [Request]; ignore query
 //     parameters in [http.Request] that are not enumerated in the [Request].
-func (r *Request) diffQuery(received *http.Request) (string, int) {
-	var output string
-	var differences int
-
+func (r *Request) matchQuery(received *http.Request) (MatchResult, bool) {
 	rQuery := r.url.Query()
 	if len(rQuery) == 0 {
-		return output, differences
+		return MatchResult{}, false
 	}
 
 	oQuery := received.URL.Query()
@@ -295,20 +1116,13 @@ func (r *Request) diffQuery(received *http.Request) (string, int) {
 	}
 	a = fmt.Sprintf("%s%s", a, a2)
 
-	eq := fmtEqual
-	if !cmp.Equal(rQuery, oFilteredQuery, cmpoptSortMaps, cmpoptSortSlices) {
-		eq = fmtNotEqual
-		differences++
-	}
-
-	output = fmt.Sprintf("\t\t     Query:  %s %s %s\n", a, eq, e)
-
-	return output, differences
+	pass := cmp.Equal(rQuery, oFilteredQuery, cmpoptSortMaps, cmpoptSortSlices)
+	return newMatchResult("Query", a, e, pass), true
 }
 
-// diffURL detects differences between a [Request]'s URL and a
-// [http.Request]'s URL. It responds with a formatted string of the
-// differences and the calculated number of differences.
+// matchURL detects differences between a [Request]'s URL and a
+// [http.Request]'s URL. The components of the URL are included as children of
+// the result.
 //
 // Ignored URL Fields:
 //   - .Opaque
@@ -317,81 +1131,70 @@ func (r *Request) diffQuery(received *http.Request) (string, int) {
 //   - .OmitHost
 //   - .ForceQuery
 //   - .RawFragment
-func (r *Request) diffURL(received *http.Request) (string, int) {
-	var output string
-	var differences int
-
-	expected, eok := diffMissing(r.url.String())
+func (r *Request) matchURL(received *http.Request) MatchResult {
+	expected, eok := diffMissing(r.urlString())
 	actual, aok := diffMissing(received.URL.String())
 	if !eok || !aok {
-		output = fmt.Sprintf("\t%d: FAIL:  %s == %s\n", 1, actual, expected)
-		differences++
-		return output, differences
+		return newMatchResult("URL", actual, expected, false)
 	}
 
-	var schemeFmt, hostFmt, pathFmt, queryFmt, fragmentFmt string
+	var children []MatchResult
 
 	e, eok := diffMissing(r.url.Scheme)
 	a, aok := diffMissing(received.URL.Scheme)
 	if eok || aok {
-		eq := fmtNotEqual
-		if cmp.Equal(r.url.Scheme, received.URL.Scheme) {
-			eq = fmtEqual
-		}
-		schemeFmt = fmt.Sprintf("\t\t    Scheme:  %s %s %s\n", a, eq, e)
+		children = append(children, newMatchResult("Scheme", a, e, cmp.Equal(r.url.Scheme, received.URL.Scheme)))
 	}
 
+	expectedURL := *r.url
 	e, eok = diffMissing(r.url.Host)
 	a, aok = diffMissing(received.URL.Host)
-	if eok || aok {
-		eq := fmtNotEqual
-		if cmp.Equal(r.url.Host, received.URL.Host) {
-			eq = fmtEqual
+	hostEqual := cmp.Equal(r.url.Host, received.URL.Host)
+	if r.hostPattern != nil {
+		host := receivedHost(received)
+		a, aok = diffMissing(host)
+		e, eok = r.hostPattern.String(), true
+		if hostEqual = r.hostPattern.matchHost(host); hostEqual {
+			// Compare the rest of the URL as if the host were equal
+			expectedURL.Host = received.URL.Host
 		}
-		hostFmt = fmt.Sprintf("\t\t      Host:  %s %s %s\n", a, eq, e)
+	}
+	if eok || aok {
+		children = append(children, newMatchResult("Host", a, e, hostEqual))
 	}
 
 	e, eok = diffMissing(r.url.Path)
 	a, aok = diffMissing(received.URL.Path)
-	if eok || aok {
-		eq := fmtNotEqual
-		if cmp.Equal(r.url.Path, received.URL.Path) {
-			eq = fmtEqual
+	pathEqual := cmp.Equal(r.url.Path, received.URL.Path)
+	if r.pathPattern != nil {
+		e = r.pathPattern.String()
+		if _, pathEqual = r.pathPattern.matchPath(received.URL); pathEqual {
+			// Compare the rest of the URL as if the path were equal
+			expectedURL.Path = received.URL.Path
+			expectedURL.RawPath = received.URL.RawPath
 		}
-		pathFmt = fmt.Sprintf("\t\t      Path:  %s %s %s\n", a, eq, e)
+	}
+	if eok || aok {
+		children = append(children, newMatchResult("Path", a, e, pathEqual))
 	}
 
-	queryFmt, queryDifferences := r.diffQuery(received)
+	queryResult, hasQuery := r.matchQuery(received)
+	if hasQuery {
+		children = append(children, queryResult)
+	}
 
 	e, eok = diffMissing(r.url.Fragment)
 	a, aok = diffMissing(received.URL.Fragment)
 	if eok || aok {
-		eq := fmtNotEqual
-		if cmp.Equal(r.url.Fragment, received.URL.Fragment) {
-			eq = fmtEqual
-		}
-		fragmentFmt = fmt.Sprintf("\t\t  Fragment:  %s %s %s\n", a, eq, e)
+		children = append(children, newMatchResult("Fragment", a, e, cmp.Equal(r.url.Fragment, received.URL.Fragment)))
 	}
 
-	if cmp.Equal(*r.url, *received.URL, cmpoptIgnoreURLRawQuery, cmpoptIgnoreURLUnexportedFields) && queryDifferences == 0 {
-		output = fmt.Sprintf("\t%d: PASS:  %s == %s\n", 1, received.URL.String(), r.url.String())
-		output += schemeFmt
-		output += hostFmt
-		output += pathFmt
-		output += queryFmt
-		output += fragmentFmt
-	} else {
-		output = fmt.Sprintf("\t%d: FAIL:  %s == %s\n", 1, received.URL.String(), r.url.String())
-		output += schemeFmt
-		output += hostFmt
-		output += pathFmt
-		output += queryFmt
-		output += fragmentFmt
-
-		differences++
-	}
+	patternsEqual := (r.hostPattern == nil || hostEqual) && (r.pathPattern == nil || pathEqual)
+	pass := patternsEqual && cmp.Equal(expectedURL, *received.URL, cmpoptIgnoreURLRawQuery, cmpoptIgnoreURLUnexportedFields) && queryResult.Differences == 0
 
-	return output, differences
+	result := newMatchResult("URL", received.URL.String(), r.urlString(), pass)
+	result.Children = children
+	return result
 }
 
 // trimBody concatenates a body larger than 1024 bytes and appends an ellipses.
@@ -406,41 +1209,164 @@ func trimBody(body []byte) string {
 	return o
 }
 
-// diffBody detects differences between a [Request]'s body and a
-// [http.Request]'s body. It responds with a formatted string of
-// the differences and the calculated number of differences.
-func (r *Request) diffBody(received *http.Request) (string, int) {
-	var output string
-	var differences int
-
+// matchBody detects differences between a [Request]'s body and a
+// [http.Request]'s body.
+func (r *Request) matchBody(received *http.Request) MatchResult {
 	otherBody, err := SafeReadBody(received)
 	if err != nil {
-		return err.Error(), 1
+		return MatchResult{Field: "Body", Message: err.Error(), Differences: 1}
 	}
-	a := trimBody(otherBody)
-	alen := len(otherBody)
+	a := fmt.Sprintf("(%d) %s", len(otherBody), trimBody(otherBody))
 
 	if string(r.body) == string(AnyBody) {
-		output = fmt.Sprintf("\t%d: PASS:  (X) %s == (0) %s\n", 2, fmtAnyBody, a)
-		return output, differences
+		return newMatchResult("Body", a, fmt.Sprintf("(X) %s", fmtAnyBody), true)
+	}
+
+	if r.typedBody != nil || r.jsonBody {
+		var result MatchResult
+		if r.typedBody != nil {
+			result = r.typedBody.match(otherBody)
+		} else {
+			result = r.matchJSONBody(otherBody)
+		}
+		result.json = true
+		return result
+	}
+
+	e := fmt.Sprintf("(%d) %s", len(r.body), trimBody(r.body))
+	return newMatchResult("Body", a, e, cmp.Equal(string(r.body), string(otherBody)))
+}
+
+// match detects differences between a [Request] and a [http.Request]. The
+// HTTP method, URL, body, and the result of each matcher are included as
+// children of the result, in that order.
+func (r *Request) match(received *http.Request) MatchResult {
+	children := []MatchResult{
+		r.matchMethod(received),
+		r.matchURL(received),
+		r.matchBody(received),
+	}
+
+	if scenario, ok := r.matchScenario(); ok {
+		children = append(children, scenario)
+	}
+
+	// Make values captured by a path pattern available to matchers, on a copy
+	// of the received request so that they are not visible to other
+	// expectations or to the caller
+	matched := received
+	if r.pathPattern != nil && len(r.matchers) > 0 {
+		matched = received.Clone(received.Context())
+		if body, err := SafeReadBody(received); err == nil {
+			matched.Body = io.NopCloser(bytes.NewBuffer(body))
+		}
+		r.setPathValues(matched)
+	}
+
+	for _, m := range r.matchers {
+		children = append(children, m.fn(matched))
+	}
+
+	result := MatchResult{Field: "Request", Children: children}
+	var passed int
+	for _, child := range children {
+		if child.Pass {
+			passed++
+		}
+		result.Differences += child.differences()
+	}
+	result.Pass = result.Differences == 0
+	result.Message = fmt.Sprintf("%d of %d passed", passed, len(children))
+
+	return result
+}
+
+// Match detects differences between a [Request] and a received [http.Request],
+// without recording the request. The result may be inspected directly or
+// formatted with [MatchResult.String].
+func (r *Request) Match(received *http.Request) MatchResult {
+	r.lock()
+	defer r.unlock()
+
+	return r.match(received)
+}
+
+// diffStatus returns the status and equality operator used to format a
+// [MatchResult] in the diff.
+func diffStatus(result MatchResult) (string, string) {
+	if result.differences() != 0 {
+		return "FAIL", fmtNotEqual
+	}
+	return "PASS", fmtEqual
+}
+
+// diffMethod detects differences between a [Request]'s HTTP method and a
+// [http.Request]'s HTTP method. It responds with a formatted string of the
+// differences and the calculated number of differences.
+func (r *Request) diffMethod(received *http.Request) (string, int) {
+	return formatMethodDiff(r.matchMethod(received))
+}
+
+// formatMethodDiff formats the result of [Request.matchMethod] for the diff.
+func formatMethodDiff(result MatchResult) (string, int) {
+	status, eq := diffStatus(result)
+	return fmt.Sprintf("\t%d: %s:  %s %s %s\n", 0, status, result.Actual, eq, result.Expected), result.differences()
+}
+
+// diffURL detects differences between a [Request]'s URL and a
+// [http.Request]'s URL. It responds with a formatted string of the
+// differences and the calculated number of differences.
+func (r *Request) diffURL(received *http.Request) (string, int) {
+	return formatURLDiff(r.matchURL(received))
+}
+
+// formatURLDiff formats the result of [Request.matchURL] for the diff, with
+// each component of the URL aligned beneath it.
+func formatURLDiff(result MatchResult) (string, int) {
+	status, _ := diffStatus(result)
+	output := fmt.Sprintf("\t%d: %s:  %s == %s\n", 1, status, result.Actual, result.Expected)
+	for _, child := range result.Children {
+		_, eq := diffStatus(child)
+		output += fmt.Sprintf("\t\t%10s:  %s %s %s\n", child.Field, child.Actual, eq, child.Expected)
 	}
+	return output, result.differences()
+}
 
-	e := trimBody(r.body)
-	elen := len(r.body)
+// diffBody detects differences between a [Request]'s body and a
+// [http.Request]'s body. It responds with a formatted string of the
+// differences and the calculated number of differences.
+func (r *Request) diffBody(received *http.Request) (string, int) {
+	return r.formatBodyDiff(r.matchBody(received))
+}
 
-	eq := fmtEqual
-	if elen == 0 && alen == 0 {
-		output = fmt.Sprintf("\t%d: PASS:  (0) %s == (0) %s\n", 2, e, a)
+// formatBodyDiff formats the result of [Request.matchBody] for the diff.
+func (r *Request) formatBodyDiff(result MatchResult) (string, int) {
+	differences := result.differences()
+	status, eq := diffStatus(result)
+
+	switch {
+	case result.Actual == "" && !result.json:
+		// The received body could not be read
+		return result.Message, differences
+	case string(r.body) == string(AnyBody):
+		_, a, _ := strings.Cut(result.Actual, " ")
+		return fmt.Sprintf("\t%d: PASS:  (X) %s == (0) %s\n", 2, fmtAnyBody, a), differences
+	case result.json:
+		output := fmt.Sprintf("\t%d: %s:  %s", 2, status, result.Message)
+		if result.Actual != "" || result.Expected != "" {
+			output += fmt.Sprintf(" %s %s %s", result.Actual, eq, result.Expected)
+		}
+		output += "\n"
+		for _, child := range result.Children {
+			output += fmt.Sprintf("\t\t  %s:  %s %s %s\n", child.Field, child.Actual, fmtNotEqual, child.Expected)
+		}
 		return output, differences
-	} else if (elen == 0 && alen > 0) || (elen > 0 && alen == 0) || !cmp.Equal(string(r.body), string(otherBody)) {
-		output = fmt.Sprintf("\t%d: FAIL:\n", 2)
-		eq = fmtNotEqual
-		differences++
-	} else {
-		output = fmt.Sprintf("\t%d: PASS:\n", 2)
+	case len(r.body) == 0 && result.Pass:
+		return fmt.Sprintf("\t%d: PASS:  %s == %s\n", 2, result.Expected, result.Actual), differences
 	}
-	output = fmt.Sprintf("%s\t\t (%d) %s\n\n\t\t    %s\n\n\t\t (%d) %s\n", output, elen, e, eq, alen, a)
 
+	output := fmt.Sprintf("\t%d: %s:\n", 2, status)
+	output = fmt.Sprintf("%s\t\t %s\n\n\t\t    %s\n\n\t\t %s\n", output, result.Expected, eq, result.Actual)
 	return output, differences
 }
 
@@ -448,31 +1374,27 @@ func (r *Request) diffBody(received *http.Request) (string, int) {
 // responds with a formatted string of the differences and the calculated
 // number of differences.
 func (r *Request) diff(received *http.Request) (string, int) {
-	output := "\n"
-	var differences int
+	result := r.match(received)
 
-	o, d := r.diffMethod(received)
-	output += o
-	differences += d
-
-	o, d = r.diffURL(received)
-	output += o
-	differences += d
-
-	o, d = r.diffBody(received)
-	output += o
-	differences += d
-
-	// 0, 1, and 2 are reserved for HTTP method, URL, and body
-	baseMatchIndex := 3
-	for i, fn := range r.matchers {
-		o, d := fn(received)
-
-		output += fmt.Sprintf("\t%d: %s\n", (baseMatchIndex + i), o)
-		differences += d
+	output := "\n"
+	for i, child := range result.Children {
+		var o string
+		switch i {
+		case 0:
+			o, _ = formatMethodDiff(child)
+		case 1:
+			o, _ = formatURLDiff(child)
+		case 2:
+			o, _ = r.formatBodyDiff(child)
+		default:
+			// 0, 1, and 2 are reserved for HTTP method, URL, and body
+			o, _ = child.output()
+			o = fmt.Sprintf("\t%d: %s\n", i, o)
+		}
+		output += o
 	}
 
-	return output, differences
+	return output, result.Differences
 }
 
 // String computes a formatted string representing a [Request].
@@ -487,7 +1409,7 @@ func (r *Request) String() string {
 	}
 	output = append(output, fmt.Sprintf("Method: %s", e))
 
-	if e = r.url.String(); e == "" {
+	if e = r.urlString(); e == "" {
 		output = append(output, fmt.Sprintf("URL: %s", fmtMissing))
 	} else {
 		output = append(output, fmt.Sprintf("URL: %s", e))
@@ -502,12 +1424,18 @@ func (r *Request) String() string {
 		if !eok {
 			e = fmtMissing
 		}
+		if r.hostPattern != nil {
+			e = r.hostPattern.String()
+		}
 		output = append(output, fmt.Sprintf("\tHost: %s", e))
 
 		e, eok = diffMissing(r.url.Path)
 		if !eok {
 			e = fmtMissing
 		}
+		if r.pathPattern != nil {
+			e = r.pathPattern.String()
+		}
 		output = append(output, fmt.Sprintf("\tPath: %s", e))
 
 		e, eok = diffMissing(r.url.RawQuery)
@@ -525,14 +1453,22 @@ func (r *Request) String() string {
 
 	if string(r.body) == string(AnyBody) {
 		output = append(output, fmt.Sprintf("Body: (X) %s", fmtAnyBody))
+	} else if r.typedBody != nil {
+		output = append(output, fmt.Sprintf("Body: %s", r.typedBody))
+	} else if r.jsonBody {
+		e = trimBody(r.body)
+		output = append(output, fmt.Sprintf("Body: (JSON) (%d) %s", len(r.body), e))
 	} else {
 		e = trimBody(r.body)
 		output = append(output, fmt.Sprintf("Body: (%d) %s", len(r.body), e))
 	}
 
-	for i, fn := range r.matchers {
-		fnName := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
-		output = append(output, fmt.Sprintf("Matcher[%d]: %s", i, fnName))
+	if r.scenario != "" {
+		output = append(output, fmt.Sprintf("Scenario: %s", r.scenarioString()))
+	}
+
+	for i, m := range r.matchers {
+		output = append(output, fmt.Sprintf("Matcher[%d]: %s", i, m.name))
 	}
 
 	return strings.Join(output, "\n")
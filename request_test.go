@@ -2,12 +2,14 @@ package httpmock
 
 import (
 	"bytes"
+	"crypto/tls"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -175,6 +177,107 @@ func Test_newRequest(t *testing.T) {
 	}
 }
 
+func Test_newReceivedRequest(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	received := mustNewRequest(http.NewRequest(http.MethodPut, "https://test.com/foo", strings.NewReader(testBody)))
+	received.Header.Set("X-Request-Id", "5678")
+	received.Trailer = http.Header{"X-Checksum": []string{"1234"}}
+	received.RemoteAddr = "10.0.0.1:1234"
+	received.TLS = &tls.ConnectionState{ServerName: "test.com"}
+
+	// Test
+	got := newReceivedRequest(m, received, []byte(testBody))
+
+	// Assertions
+	assert.Equal(t, http.MethodPut, got.method)
+	assert.Equal(t, received.URL, got.url)
+	assert.Equal(t, []byte(testBody), got.body)
+	assert.Equal(t, http.Header{"X-Request-Id": []string{"5678"}}, got.Header())
+	assert.Equal(t, http.Header{"X-Checksum": []string{"1234"}}, got.Trailer())
+	assert.Equal(t, "test.com", got.Host())
+	assert.Equal(t, "HTTP/1.1", got.Proto())
+	assert.Equal(t, "10.0.0.1:1234", got.RemoteAddr())
+	assert.Equal(t, int64(len(testBody)), got.ContentLength())
+	assert.Equal(t, "test.com", got.TLS().ServerName)
+
+	// Modifying the received request should not modify the recorded request
+	received.Header.Set("X-Request-Id", "0000")
+	assert.Equal(t, "5678", got.Header().Get("X-Request-Id"))
+}
+
+func TestRequest_Getters(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	r := m.On(http.MethodPut, "https://test.com/foo?limit=1", []byte(testBody))
+	resp := r.RespondNoContent()
+	r.totalRequests = 3
+
+	// Test and Assertions
+	assert.Equal(t, http.MethodPut, r.Method())
+	assert.Equal(t, "https://test.com/foo?limit=1", r.URL().String())
+	assert.Equal(t, []byte(testBody), r.Body())
+	assert.Equal(t, resp.statusCode, r.Response().StatusCode())
+	assert.Len(t, r.Responses(), 1)
+	assert.Equal(t, 3, r.TotalRequests())
+
+	// Modifying the returned values should not modify the request
+	r.URL().Path = "/bar"
+	r.Body()[0] = 'J'
+	r.Response().header.Set("next", "abcd")
+	assert.Equal(t, "/foo", r.url.Path)
+	assert.Equal(t, []byte(testBody), r.body)
+	assert.NotSame(t, resp, r.Response())
+	assert.Empty(t, resp.header)
+}
+
+func TestRequest_snapshot(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	r := m.On(http.MethodPut, "https://test.com/foo", []byte(testBody)).Matches(testRequestMatcherAlwaysPass)
+	r.RespondOK([]byte(testBody)).Header("next", "abcd")
+
+	// Test
+	got := r.snapshot()
+
+	// Assertions
+	assert.Equal(t, r.method, got.method)
+	assert.Equal(t, r.url, got.url)
+	assert.Equal(t, r.body, got.body)
+	assert.Len(t, got.matchers, 1)
+	assert.Equal(t, r.response.header, got.response.header)
+	got.url.Path = "/bar"
+	got.body[0] = 'J'
+	got.matchers[0] = newRequestMatcher(testRequestMatcherAlwaysFail)
+	got.response.header["next"][0] = "efgh"
+	assert.Equal(t, "/foo", r.url.Path)
+	assert.Equal(t, []byte(testBody), r.body)
+	assert.Equal(t, []string{"abcd"}, r.response.header["next"])
+	assert.Equal(t, "GOOD == GOOD", r.matchers[0].fn(nil).Message)
+}
+
+func TestRequest_snapshot_Detached(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	r := m.On(http.MethodPut, "https://test.com/foo", []byte(testBody)).After(m.On(http.MethodGet, "/", nil))
+	r.RespondOK([]byte(testBody)).ThenRespond(http.StatusTeapot, nil)
+
+	// Test
+	got := r.snapshot()
+
+	// Assertions
+	assert.Nil(t, got.parent)
+	assert.Nil(t, got.prerequisites)
+	assert.Same(t, got.response.parent, got.sequence[0].parent)
+	assert.PanicsWithValue(t, msgModifySnapshot, func() { got.Once() })
+	assert.PanicsWithValue(t, msgModifySnapshot, func() { got.Unset() })
+	assert.PanicsWithValue(t, msgModifySnapshot, func() { got.WaitUntilCalled(time.Millisecond) })
+	assert.PanicsWithValue(t, msgModifySnapshot, func() { got.Response().Header("next", "abcd") })
+	assert.PanicsWithValue(t, msgModifySnapshot, func() { r.Response().ThenRespond(http.StatusOK, nil) })
+	assert.Len(t, r.sequence, 1)
+	assert.Empty(t, r.response.header)
+}
+
 func TestRequest_Respond(t *testing.T) {
 	// Setup
 	r := &Request{parent: new(Mock)}
@@ -228,6 +331,34 @@ func TestRequest_RespondNoContent(t *testing.T) {
 	assert.Equal(t, got, r.response)
 }
 
+func TestRequest_RespondJSON(t *testing.T) {
+	// Setup
+	r := &Request{parent: new(Mock).Test(t)}
+
+	// Test
+	got := r.RespondJSON(http.StatusCreated, map[string]any{"id": "1234", "count": 2})
+
+	// Assertions
+	want := &Response{
+		parent:     r,
+		header:     http.Header{"Content-Type": []string{"application/json"}},
+		statusCode: http.StatusCreated,
+		body:       []byte(`{"count":2,"id":"1234"}`),
+	}
+	assert.Equal(t, want, got)
+	assert.Equal(t, got, r.response)
+}
+
+func TestRequest_RespondJSON_Fail(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	r := &Request{parent: new(Mock).Test(mockT)}
+
+	// Test and Assertions
+	assert.PanicsWithValue(t, "FailNow was called", func() { r.RespondJSON(http.StatusOK, make(chan int)) })
+	assert.Equal(t, 1, mockT.errorfCount)
+}
+
 func TestRequest_RespondUsing(t *testing.T) {
 	// Setup
 	r := &Request{parent: new(Mock)}
@@ -296,6 +427,380 @@ func TestRequest_Times(t *testing.T) {
 	assert.Equal(t, 4, r.repeatability)
 }
 
+func TestRequest_After(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	first := m.On(http.MethodPost, "/token", nil)
+	second := m.On(http.MethodGet, "/config", nil)
+	r := m.On(http.MethodGet, "/resource", nil)
+
+	// Test
+	r.After(first).After(second)
+
+	// Assertions
+	assert.Equal(t, []*Request{first, second}, r.prerequisites)
+}
+
+func TestInOrder(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	first := m.On(http.MethodPost, "/token", nil)
+	second := m.On(http.MethodGet, "/config", nil)
+	third := m.On(http.MethodGet, "/resource", nil)
+
+	// Test
+	InOrder(first, second, third)
+
+	// Assertions
+	assert.Empty(t, first.prerequisites)
+	assert.Equal(t, []*Request{first}, second.prerequisites)
+	assert.Equal(t, []*Request{second}, third.prerequisites)
+}
+
+func TestRequest_Unset(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	first := m.On(http.MethodPost, "/token", nil)
+	second := m.On(http.MethodGet, "/config", nil)
+	third := m.On(http.MethodGet, "/resource", nil).After(first, second)
+
+	// Test
+	got := first.Unset()
+
+	// Assertions
+	assert.Same(t, first, got)
+	assert.Equal(t, []*Request{second, third}, m.ExpectedRequests)
+	assert.Equal(t, []*Request{second}, third.prerequisites)
+
+	first.Unset()
+	assert.Equal(t, []*Request{second, third}, m.ExpectedRequests)
+}
+
+func TestRequest_Unset_Fallback(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	expected := m.On(http.MethodGet, "https://test.com/foo", nil)
+	fallback := m.OnUnmatched()
+	fallback.RespondOK([]byte("fallback"))
+
+	// Test
+	got := fallback.Unset()
+
+	// Assertions
+	assert.Same(t, fallback, got)
+	assert.Nil(t, m.fallback)
+	assert.Equal(t, []*Request{expected}, m.ExpectedRequests)
+
+	_, err := m.requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/bar", http.NoBody)))
+	assert.ErrorContains(t, err, "closest request")
+	assert.NotSame(t, fallback, m.OnUnmatched())
+}
+
+func TestRequest_WaitUntilCalled(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests int
+		timeout  time.Duration
+		want     bool
+	}{
+		{
+			name:     "called",
+			requests: 2,
+			timeout:  5 * time.Second,
+			want:     true,
+		},
+		{
+			name:     "timeout",
+			requests: 1,
+			timeout:  0,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock)
+			r := m.On(http.MethodPost, "https://test.com/webhooks", nil).RespondNoContent().Twice()
+
+			sent := make(chan struct{})
+			go func() {
+				defer close(sent)
+				for range tt.requests {
+					m.Requested(mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/webhooks", http.NoBody)))
+				}
+			}()
+
+			// Only time out once every request has been sent, so that the
+			// outcome does not depend on scheduling.
+			if !tt.want {
+				<-sent
+			}
+
+			// Test
+			got := r.WaitUntilCalled(tt.timeout)
+
+			// Assertions
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRequest_Hooks(t *testing.T) {
+	// Setup
+	r := Request{parent: new(Mock)}
+	var called bool
+	notify := make(chan struct{})
+	wait := make(chan time.Time)
+
+	// Test
+	got := r.Run(func(*http.Request) { called = true }).NotifyOn(notify).WaitUntil(wait)
+
+	// Assertions
+	assert.Same(t, &r, got)
+	r.hooks.run(nil)
+	assert.True(t, called)
+	assert.Equal(t, (chan<- struct{})(notify), r.hooks.notify)
+	assert.Equal(t, (<-chan time.Time)(wait), r.hooks.wait)
+}
+
+func TestRequest_satisfied(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *Request
+		want    bool
+	}{
+		{
+			name:    "not-requested",
+			request: &Request{},
+			want:    false,
+		},
+		{
+			name:    "unlimited",
+			request: &Request{totalRequests: 1},
+			want:    true,
+		},
+		{
+			name:    "times-remaining",
+			request: &Request{repeatability: 1, totalRequests: 1},
+			want:    false,
+		},
+		{
+			name:    "times-exhausted",
+			request: &Request{repeatability: -1, totalRequests: 2},
+			want:    true,
+		},
+		{
+			name:    "sequence-remaining",
+			request: &Request{sequence: []*Response{{}}, totalRequests: 1},
+			want:    false,
+		},
+		{
+			name:    "sequence-returned",
+			request: &Request{sequence: []*Response{{}}, totalRequests: 2},
+			want:    true,
+		},
+		{
+			name:    "maybe-not-requested",
+			request: &Request{optional: true},
+			want:    true,
+		},
+		{
+			name:    "maybe-sequence-remaining",
+			request: &Request{optional: true, sequence: []*Response{{}, {}}, totalRequests: 1},
+			want:    true,
+		},
+		{
+			name:    "maybe-times-remaining",
+			request: &Request{optional: true, repeatability: 1, sequence: []*Response{{}}, totalRequests: 1},
+			want:    false,
+		},
+		{
+			name:    "maybe-at-least-remaining",
+			request: &Request{optional: true, countRange: true, minRequests: 2, sequence: []*Response{{}}, totalRequests: 1},
+			want:    false,
+		},
+		{
+			name:    "at-least-remaining",
+			request: &Request{countRange: true, minRequests: 2, totalRequests: 1},
+			want:    false,
+		},
+		{
+			name:    "at-least-met",
+			request: &Request{countRange: true, minRequests: 2, totalRequests: 3},
+			want:    true,
+		},
+		{
+			name:    "at-most-not-requested",
+			request: &Request{countRange: true, repeatability: 3},
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got := tt.request.satisfied()
+
+			// Assertions
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRequest_CountRange(t *testing.T) {
+	tests := []struct {
+		name              string
+		configure         func(r *Request) *Request
+		wantRepeatability int
+		wantMinRequests   int
+		wantOptional      bool
+	}{
+		{
+			name:              "maybe",
+			configure:         func(r *Request) *Request { return r.Maybe() },
+			wantRepeatability: 0,
+			wantMinRequests:   0,
+			wantOptional:      true,
+		},
+		{
+			name:              "at-least",
+			configure:         func(r *Request) *Request { return r.AtLeast(2) },
+			wantRepeatability: 0,
+			wantMinRequests:   2,
+		},
+		{
+			name:              "at-most",
+			configure:         func(r *Request) *Request { return r.AtMost(3) },
+			wantRepeatability: 3,
+			wantMinRequests:   0,
+		},
+		{
+			name:              "at-most-zero",
+			configure:         func(r *Request) *Request { return r.AtMost(0) },
+			wantRepeatability: -1,
+			wantMinRequests:   0,
+		},
+		{
+			name:              "between",
+			configure:         func(r *Request) *Request { return r.Between(2, 5) },
+			wantRepeatability: 5,
+			wantMinRequests:   2,
+		},
+		{
+			name:              "between-reversed",
+			configure:         func(r *Request) *Request { return r.Between(5, 2) },
+			wantRepeatability: 5,
+			wantMinRequests:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			r := &Request{parent: new(Mock)}
+
+			// Test
+			got := tt.configure(r)
+
+			// Assertions
+			assert.Same(t, r, got)
+			assert.Equal(t, tt.wantRepeatability, r.repeatability)
+			assert.Equal(t, tt.wantMinRequests, r.minRequests)
+			assert.Equal(t, tt.wantOptional, r.optional)
+			assert.Equal(t, !tt.wantOptional, r.countRange)
+		})
+	}
+}
+
+func TestRequest_Times_ResetsCountRange(t *testing.T) {
+	// Setup
+	r := Request{parent: new(Mock)}
+	r.Between(2, 5)
+
+	// Test
+	r.Times(4)
+
+	// Assertions
+	assert.Equal(t, 4, r.repeatability)
+	assert.False(t, r.countRange)
+	assert.Zero(t, r.minRequests)
+}
+
+func TestRequest_Times_ShorterThanSequence(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(r *Request)
+	}{
+		{
+			name: "times-after-sequence",
+			configure: func(r *Request) {
+				r.RespondOK(nil).ThenRespond(http.StatusTeapot, nil).ThenRespondOK(nil)
+				r.Times(2)
+			},
+		},
+		{
+			name: "sequence-after-times",
+			configure: func(r *Request) {
+				r.Once().RespondOK(nil).ThenRespond(http.StatusTeapot, nil)
+			},
+		},
+		{
+			name: "at-most",
+			configure: func(r *Request) {
+				r.RespondOK(nil).ThenRespond(http.StatusTeapot, nil).AtMost(1)
+			},
+		},
+		{
+			name: "between",
+			configure: func(r *Request) {
+				r.RespondOK(nil).ThenRespond(http.StatusTeapot, nil).Between(0, 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			var successfulConfigureCall int
+
+			mockT := new(MockTestingT)
+			r := new(Mock).Test(mockT).On(http.MethodGet, "/", nil)
+
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					t.Fatal("Did not expect to get here")
+				}
+				// Assertions
+				assert.Equal(t, "FailNow was called", rec.(string))
+				assert.Equal(t, 1, mockT.errorfCount)
+				assert.Equal(t, 1, mockT.failNowCount)
+				assert.Zero(t, successfulConfigureCall)
+			}()
+
+			// Test
+			tt.configure(r)
+			successfulConfigureCall++
+		})
+	}
+}
+
+func TestRequest_Times_CoversSequence(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	r := new(Mock).Test(mockT).On(http.MethodGet, "/", nil)
+
+	// Test
+	r.RespondOK(nil).ThenRespond(http.StatusTeapot, nil).Twice()
+	r.AtMost(3)
+	r.Between(1, 0)
+
+	// Assertions
+	assert.Zero(t, mockT.errorfCount)
+	assert.Zero(t, mockT.failNowCount)
+}
+
 func TestRequest_Matches(t *testing.T) {
 	// Setup
 	r := Request{parent: new(Mock)}
@@ -326,14 +831,129 @@ func TestRequest_Matches(t *testing.T) {
 	}
 	for i, m := range r.matchers {
 		w := want[i]
-		wantOutput := w[0]
+		wantMessage := w[0]
 		wantDifferences := w[1]
 
-		gotOutput, gotDifferences := m(&http.Request{})
+		got := m.fn(&http.Request{})
+
+		assert.Equal(t, wantMessage, got.Message)
+		assert.Equal(t, wantDifferences, got.Differences)
+	}
+}
+
+func TestRequest_Matches_ResultMatcher(t *testing.T) {
+	// Setup
+	r := Request{parent: new(Mock)}
+	resultFn := func(received *http.Request) MatchResult {
+		return newMatchResult("header Accept", received.Header.Get("Accept"), "*/*", true)
+	}
+
+	// Test
+	r.Matches(testRequestMatcherAlwaysPass, HeaderPresent("Accept"), HeaderAbsent("Accept"), resultFn)
+
+	// Assertions
+	assert.Len(t, r.matchers, 4)
+	assert.Equal(t, "github.com/shawalli/httpmock.testRequestMatcherAlwaysPass", r.matchers[0].name)
+
+	received := &http.Request{Header: http.Header{"Accept": []string{"*/*"}}}
+	assert.True(t, r.matchers[0].fn(received).Pass)
+	assert.Equal(t, newMatchResult("header Accept", fmtPresent, fmtPresent, true), r.matchers[1].fn(received))
+	assert.False(t, r.matchers[2].fn(received).Pass)
+	assert.True(t, r.matchers[3].fn(received).Pass)
+}
+
+func TestRequest_Matches_Unsupported(t *testing.T) {
+	// Setup
+	var successfulMatchesCall int
+
+	mockT := new(MockTestingT)
+	r := new(Mock).Test(mockT).On(http.MethodGet, "/", nil)
+
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			t.Fatal("Did not expect to get here")
+		}
+		// Assertions
+		assert.Equal(t, "FailNow was called", rec.(string))
+		assert.Equal(t, 1, mockT.failNowCount)
+		assert.Zero(t, successfulMatchesCall)
+		assert.Empty(t, r.matchers)
+	}()
+
+	// Test
+	r.Matches(HeaderPresent("Accept"), "Accept")
+	successfulMatchesCall++
+}
+
+func TestRequest_Match(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	r := m.On(http.MethodPost, "https://test.com/foo?page=2", []byte(testBody)).
+		Matches(testRequestMatcherAlwaysPass, HeaderEquals("Accept", "application/json"))
+
+	received := mustNewRequest(http.NewRequest(http.MethodPut, "https://test.com/foo?page=3", strings.NewReader("Hi World.")))
+	received.Header.Set("Accept", "application/json")
+
+	// Test
+	got := r.Match(received)
+
+	// Assertions
+	assert.False(t, got.Pass)
+	assert.Equal(t, 3, got.Differences)
+	assert.Equal(t, "2 of 5 passed", got.Message)
+	assert.Len(t, got.Children, 5)
+
+	assert.Equal(t, MatchResult{Field: "Method", Actual: http.MethodPut, Expected: http.MethodPost, Differences: 1}, got.Children[0])
+
+	assert.Equal(t, "URL", got.Children[1].Field)
+	assert.False(t, got.Children[1].Pass)
+	assert.Equal(t, []MatchResult{
+		newMatchResult("Scheme", "https", "https", true),
+		newMatchResult("Host", "test.com", "test.com", true),
+		newMatchResult("Path", "/foo", "/foo", true),
+		newMatchResult("Query", "page=3 (page=3)", "page=2", false),
+	}, got.Children[1].Children)
 
-		assert.Equal(t, wantOutput, gotOutput)
-		assert.Equal(t, wantDifferences, gotDifferences)
+	assert.Equal(t, newMatchResult("Body", "(9) Hi World.", "(12) Hello World!", false), got.Children[2])
+	assert.Equal(t, MatchResult{Message: "GOOD == GOOD", Pass: true}, got.Children[3])
+	assert.Equal(t, newMatchResult("header Accept", `"application/json"`, `"application/json"`, true), got.Children[4])
+
+	wantDiff := "\n" +
+		"\t0: FAIL:  PUT != POST\n" +
+		"\t1: FAIL:  https://test.com/foo?page=3 == https://test.com/foo?page=2\n" +
+		"\t\t    Scheme:  https == https\n" +
+		"\t\t      Host:  test.com == test.com\n" +
+		"\t\t      Path:  /foo == /foo\n" +
+		"\t\t     Query:  page=3 (page=3) != page=2\n" +
+		"\t2: FAIL:\n\t\t (12) Hello World!\n\n\t\t    !=\n\n\t\t (9) Hi World.\n" +
+		"\t3: PASS:  GOOD == GOOD\n" +
+		"\t4: PASS:  header Accept: \"application/json\" == \"application/json\"\n"
+	gotDiff, gotDifferences := r.diff(received)
+	assert.Equal(t, wantDiff, gotDiff)
+	assert.Equal(t, 3, gotDifferences)
+}
+
+func TestRequest_Match_PathValues(t *testing.T) {
+	// Setup
+	var gotID string
+	captureID := func(received *http.Request) (output string, differences int) {
+		gotID = received.PathValue("id")
+		return "PASS:  captured id", 0
 	}
+
+	m := new(Mock)
+	r := m.On(http.MethodGet, "https://test.com/users/{id}", nil).Matches(captureID)
+
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/users/123", http.NoBody))
+
+	// Test
+	got := r.Match(received)
+
+	// Assertions
+	assert.True(t, got.Pass)
+	assert.Equal(t, "123", gotID)
+	assert.Empty(t, received.PathValue("id"))
 }
 
 func TestRequest_diffMethod(t *testing.T) {
@@ -370,57 +990,340 @@ func TestRequest_diffMethod(t *testing.T) {
 			wantDifferences: false,
 		},
 		{
-			name:            "any-method-delete",
-			request:         &Request{method: AnyMethod},
-			received:        &http.Request{Method: http.MethodDelete},
-			wantDifferences: false,
+			name:            "any-method-delete",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodDelete},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-get",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodGet},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-head",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodHead},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-options",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodOptions},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-patch",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodPatch},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-post",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodPost},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-put",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodPut},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-trace",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodTrace},
+			wantDifferences: false,
+		},
+		{
+			name:            "equal",
+			request:         &Request{method: http.MethodGet},
+			received:        &http.Request{Method: http.MethodGet},
+			wantDifferences: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got, gotDifferences := tt.request.diffMethod(tt.received)
+
+			// Assertions
+			assert.NotEmpty(t, got)
+			assert.Equal(t, tt.wantDifferences, gotDifferences != 0)
+		})
+	}
+}
+
+func TestRequest_matchMethod(t *testing.T) {
+	tests := []struct {
+		name            string
+		request         *Request
+		received        *http.Request
+		wantDifferences bool
+	}{
+		{
+			name:            "missing-request-method",
+			request:         &Request{},
+			received:        &http.Request{Method: http.MethodGet},
+			wantDifferences: true,
+		},
+		{
+			name: "missing-received-method",
+			request: &Request{
+				method: http.MethodGet,
+			},
+			received:        &http.Request{},
+			wantDifferences: true,
+		},
+		{
+			name:            "different-methods",
+			request:         &Request{method: http.MethodGet},
+			received:        &http.Request{Method: http.MethodPost},
+			wantDifferences: true,
+		},
+		{
+			name:            "any-method-connect",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodConnect},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-delete",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodDelete},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-get",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodGet},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-head",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodHead},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-options",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodOptions},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-patch",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodPatch},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-post",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodPost},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-put",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodPut},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-method-trace",
+			request:         &Request{method: AnyMethod},
+			received:        &http.Request{Method: http.MethodTrace},
+			wantDifferences: false,
+		},
+		{
+			name:            "equal",
+			request:         &Request{method: http.MethodGet},
+			received:        &http.Request{Method: http.MethodGet},
+			wantDifferences: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got := tt.request.matchMethod(tt.received)
+
+			// Assertions
+			assert.NotEmpty(t, got.String())
+			assert.Equal(t, tt.wantDifferences, got.Differences != 0)
+			assert.Equal(t, tt.wantDifferences, !got.Pass)
+		})
+	}
+}
+
+func TestRequest_diffURL(t *testing.T) {
+	tests := []struct {
+		name            string
+		request         *Request
+		received        *http.Request
+		wantDifferences bool
+	}{
+		{
+			name:            "missing-request-url",
+			request:         &Request{url: &url.URL{}},
+			received:        &http.Request{URL: &url.URL{Path: "test.com"}},
+			wantDifferences: true,
+		},
+		{
+			name:            "missing-received-url",
+			request:         &Request{url: &url.URL{Path: "test.com"}},
+			received:        &http.Request{URL: &url.URL{}},
+			wantDifferences: true,
+		},
+		{
+			name:            "missing-both-url",
+			request:         &Request{url: &url.URL{}},
+			received:        &http.Request{URL: &url.URL{}},
+			wantDifferences: true,
+		},
+		{
+			name:            "missing-request-scheme",
+			request:         &Request{url: &url.URL{}},
+			received:        &http.Request{URL: &url.URL{Scheme: "http"}},
+			wantDifferences: true,
+		},
+		{
+			name:            "missing-received-scheme",
+			request:         &Request{url: &url.URL{Scheme: "http"}},
+			received:        &http.Request{URL: &url.URL{}},
+			wantDifferences: true,
+		},
+		{
+			name:            "different-schemes",
+			request:         &Request{url: &url.URL{Scheme: "http"}},
+			received:        &http.Request{URL: &url.URL{Scheme: "https"}},
+			wantDifferences: true,
+		},
+		{
+			name:            "missing-request-host",
+			request:         &Request{url: &url.URL{}},
+			received:        &http.Request{URL: &url.URL{Host: "test.com"}},
+			wantDifferences: true,
+		},
+		{
+			name:            "missing-received-host",
+			request:         &Request{url: &url.URL{Host: "test.com"}},
+			received:        &http.Request{URL: &url.URL{}},
+			wantDifferences: true,
+		},
+		{
+			name:            "different-hosts",
+			request:         &Request{url: &url.URL{Host: "test.com"}},
+			received:        &http.Request{URL: &url.URL{Host: "notest.com"}},
+			wantDifferences: true,
+		},
+		{
+			name:            "missing-request-path",
+			request:         &Request{url: &url.URL{}},
+			received:        &http.Request{URL: &url.URL{Path: "/foo"}},
+			wantDifferences: true,
+		},
+		{
+			name:            "missing-received-path",
+			request:         &Request{url: &url.URL{Path: "/foo"}},
+			received:        &http.Request{URL: &url.URL{}},
+			wantDifferences: true,
+		},
+		{
+			name:            "different-path",
+			request:         &Request{url: &url.URL{Path: "/foo"}},
+			received:        &http.Request{URL: &url.URL{Path: "/bar"}},
+			wantDifferences: true,
 		},
 		{
-			name:            "any-method-get",
-			request:         &Request{method: AnyMethod},
-			received:        &http.Request{Method: http.MethodGet},
-			wantDifferences: false,
+			name:            "missing-received-query",
+			request:         &Request{url: &url.URL{RawQuery: "limit=5"}},
+			received:        &http.Request{URL: &url.URL{}},
+			wantDifferences: true,
 		},
 		{
-			name:            "any-method-head",
-			request:         &Request{method: AnyMethod},
-			received:        &http.Request{Method: http.MethodHead},
-			wantDifferences: false,
+			name:            "different-queries",
+			request:         &Request{url: &url.URL{RawQuery: "limit=5"}},
+			received:        &http.Request{URL: &url.URL{RawQuery: "offset=10"}},
+			wantDifferences: true,
 		},
 		{
-			name:            "any-method-options",
-			request:         &Request{method: AnyMethod},
-			received:        &http.Request{Method: http.MethodOptions},
-			wantDifferences: false,
+			name:            "different-query-values",
+			request:         &Request{url: &url.URL{RawQuery: "limit=5"}},
+			received:        &http.Request{URL: &url.URL{RawQuery: "limit=10"}},
+			wantDifferences: true,
 		},
 		{
-			name:            "any-method-patch",
-			request:         &Request{method: AnyMethod},
-			received:        &http.Request{Method: http.MethodPatch},
-			wantDifferences: false,
+			name:            "different-query-valuesets",
+			request:         &Request{url: &url.URL{RawQuery: "limit=5"}},
+			received:        &http.Request{URL: &url.URL{RawQuery: "limit=10&limit=5"}},
+			wantDifferences: true,
 		},
 		{
-			name:            "any-method-post",
-			request:         &Request{method: AnyMethod},
-			received:        &http.Request{Method: http.MethodPost},
+			name: "equal",
+			request: &Request{url: &url.URL{
+				Scheme:   "https",
+				Host:     "test.com",
+				Path:     "/foo",
+				Fragment: "top",
+			}},
+			received: &http.Request{URL: &url.URL{
+				Scheme:   "https",
+				Host:     "test.com",
+				Path:     "/foo",
+				Fragment: "top",
+			}},
 			wantDifferences: false,
 		},
 		{
-			name:            "any-method-put",
-			request:         &Request{method: AnyMethod},
-			received:        &http.Request{Method: http.MethodPut},
+			name: "equal-query",
+			request: &Request{url: &url.URL{
+				Scheme:   "https",
+				Host:     "test.com",
+				Path:     "/foo",
+				RawQuery: "limit=5&offset=10&next=abcd",
+			}},
+			received: &http.Request{URL: &url.URL{
+				Scheme:   "https",
+				Host:     "test.com",
+				Path:     "/foo",
+				RawQuery: "limit=5&offset=10&next=abcd",
+			}},
 			wantDifferences: false,
 		},
 		{
-			name:            "any-method-trace",
-			request:         &Request{method: AnyMethod},
-			received:        &http.Request{Method: http.MethodTrace},
+			name: "equal-query-subset",
+			request: &Request{url: &url.URL{
+				Scheme:   "https",
+				Host:     "test.com",
+				Path:     "/foo",
+				RawQuery: "limit=5",
+			}},
+			received: &http.Request{URL: &url.URL{
+				Scheme:   "https",
+				Host:     "test.com",
+				Path:     "/foo",
+				RawQuery: "limit=5&offset=10&next=abcd",
+			}},
 			wantDifferences: false,
 		},
 		{
-			name:            "equal",
-			request:         &Request{method: http.MethodGet},
-			received:        &http.Request{Method: http.MethodGet},
+			name: "equal-query-unordered",
+			request: &Request{url: &url.URL{
+				Scheme:   "https",
+				Host:     "test.com",
+				Path:     "/foo",
+				RawQuery: "limit=5&next=abcd&offset=10",
+			}},
+			received: &http.Request{URL: &url.URL{
+				Scheme:   "https",
+				Host:     "test.com",
+				Path:     "/foo",
+				RawQuery: "next=abcd&offset=10&limit=5",
+			}},
 			wantDifferences: false,
 		},
 	}
@@ -428,7 +1331,7 @@ func TestRequest_diffMethod(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Test
-			got, gotDifferences := tt.request.diffMethod(tt.received)
+			got, gotDifferences := tt.request.diffURL(tt.received)
 
 			// Assertions
 			assert.NotEmpty(t, got)
@@ -437,7 +1340,7 @@ func TestRequest_diffMethod(t *testing.T) {
 	}
 }
 
-func TestRequest_diffURL(t *testing.T) {
+func TestRequest_matchURL(t *testing.T) {
 	tests := []struct {
 		name            string
 		request         *Request
@@ -609,15 +1512,72 @@ func TestRequest_diffURL(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Test
-			got, gotDifferences := tt.request.diffURL(tt.received)
+			got := tt.request.matchURL(tt.received)
 
 			// Assertions
-			assert.NotEmpty(t, got)
-			assert.Equal(t, tt.wantDifferences, gotDifferences != 0)
+			assert.NotEmpty(t, got.String())
+			assert.Equal(t, tt.wantDifferences, got.Differences != 0)
+			assert.Equal(t, tt.wantDifferences, !got.Pass)
 		})
 	}
 }
 
+func TestRequest_diffURL_Pattern(t *testing.T) {
+	// Setup
+	pathPattern, err := newRegexpPattern(`/v[0-9]+/health`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostPattern, err := newGlobPattern("*.internal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Request{
+		url:         &url.URL{Path: `/v[0-9]+/health`},
+		pathPattern: pathPattern,
+		hostPattern: hostPattern,
+	}
+
+	// Test and Assertions
+	received := &http.Request{URL: &url.URL{Path: "/v2/health"}, Host: "api.internal"}
+	got, gotDifferences := r.diffURL(received)
+	assert.Equal(t, 0, gotDifferences)
+	assert.Equal(t, "\t1: PASS:  /v2/health == //*.internal/v[0-9]+/health\n\t\t      Host:  api.internal == *.internal\n\t\t      Path:  /v2/health == /v[0-9]+/health\n", got)
+
+	received = &http.Request{URL: &url.URL{Path: "/health"}, Host: "api.external"}
+	got, gotDifferences = r.diffURL(received)
+	assert.Equal(t, 1, gotDifferences)
+	assert.Equal(t, "\t1: FAIL:  /health == //*.internal/v[0-9]+/health\n\t\t      Host:  api.external != *.internal\n\t\t      Path:  /health != /v[0-9]+/health\n", got)
+}
+
+func TestRequest_matchURL_Pattern(t *testing.T) {
+	// Setup
+	pathPattern, err := newRegexpPattern(`/v[0-9]+/health`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostPattern, err := newGlobPattern("*.internal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Request{
+		url:         &url.URL{Path: `/v[0-9]+/health`},
+		pathPattern: pathPattern,
+		hostPattern: hostPattern,
+	}
+
+	// Test and Assertions
+	received := &http.Request{URL: &url.URL{Path: "/v2/health"}, Host: "api.internal"}
+	got := r.matchURL(received)
+	assert.Equal(t, 0, got.Differences)
+	assert.Equal(t, "PASS:  URL: /v2/health == //*.internal/v[0-9]+/health\n\t0: PASS:  Host: api.internal == *.internal\n\t1: PASS:  Path: /v2/health == /v[0-9]+/health", got.String())
+
+	received = &http.Request{URL: &url.URL{Path: "/health"}, Host: "api.external"}
+	got = r.matchURL(received)
+	assert.Equal(t, 1, got.Differences)
+	assert.Equal(t, "FAIL:  URL: /health != //*.internal/v[0-9]+/health\n\t0: FAIL:  Host: api.external != *.internal\n\t1: FAIL:  Path: /health != /v[0-9]+/health", got.String())
+}
+
 func TestRequest_diffBody_FailToReadBody(t *testing.T) {
 	// Setup
 	r := &Request{}
@@ -632,6 +1592,21 @@ func TestRequest_diffBody_FailToReadBody(t *testing.T) {
 	assert.Equal(t, 1, gotDifferences)
 }
 
+func TestRequest_matchBody_FailToReadBody(t *testing.T) {
+	// Setup
+	r := &Request{}
+
+	received := mustNewRequest(http.NewRequest(http.MethodPut, "https://test.com/foo", io.NopCloser(&badReader{})))
+
+	// Test
+	got := r.matchBody(received)
+
+	// Assertions
+	assert.Contains(t, got.String(), ErrReadBody.Error())
+	assert.Equal(t, 1, got.Differences)
+	assert.False(t, got.Pass)
+}
+
 func TestRequest_diffBody(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -719,6 +1694,94 @@ func TestRequest_diffBody(t *testing.T) {
 	}
 }
 
+func TestRequest_matchBody(t *testing.T) {
+	tests := []struct {
+		name            string
+		request         *Request
+		received        *http.Request
+		wantDifferences bool
+	}{
+		{
+			name:            "missing-request-body",
+			request:         &Request{},
+			received:        &http.Request{Body: io.NopCloser(strings.NewReader("Hello World!"))},
+			wantDifferences: true,
+		},
+		{
+			name:            "missing-received-body",
+			request:         &Request{body: []byte(testBody)},
+			received:        &http.Request{Body: http.NoBody},
+			wantDifferences: true,
+		},
+		{
+			name:            "different-bodies",
+			request:         &Request{body: []byte(testBody)},
+			received:        &http.Request{Body: io.NopCloser(strings.NewReader("Hello World."))},
+			wantDifferences: true,
+		},
+		{
+			name:            "missing-both-bodies",
+			request:         &Request{},
+			received:        &http.Request{Body: http.NoBody},
+			wantDifferences: false,
+		},
+		{
+			name:            "same-bodies",
+			request:         &Request{body: []byte("Hello World!")},
+			received:        &http.Request{Body: io.NopCloser(strings.NewReader("Hello World!"))},
+			wantDifferences: false,
+		},
+		{
+			name:            "long-request-body",
+			request:         &Request{body: testLongBody},
+			received:        &http.Request{Body: io.NopCloser(strings.NewReader("Hello World!"))},
+			wantDifferences: true,
+		},
+		{
+			name:            "long-received-body",
+			request:         &Request{},
+			received:        &http.Request{Body: io.NopCloser(bytes.NewBuffer(testLongBody))},
+			wantDifferences: true,
+		},
+		{
+			name:            "long-both-bodies",
+			request:         &Request{body: testLongBody},
+			received:        &http.Request{Body: io.NopCloser(bytes.NewBuffer(testLongBody))},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-body-no-received-body",
+			request:         &Request{body: AnyBody},
+			received:        &http.Request{Body: http.NoBody},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-body-received-body",
+			request:         &Request{body: AnyBody},
+			received:        &http.Request{Body: io.NopCloser(strings.NewReader("Hello World!"))},
+			wantDifferences: false,
+		},
+		{
+			name:            "any-body-received-long-body",
+			request:         &Request{body: AnyBody},
+			received:        &http.Request{Body: io.NopCloser(bytes.NewBuffer(testLongBody))},
+			wantDifferences: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got := tt.request.matchBody(tt.received)
+
+			// Assertions
+			assert.NotEmpty(t, got.String())
+			assert.Equal(t, tt.wantDifferences, got.Differences != 0)
+			assert.Equal(t, tt.wantDifferences, !got.Pass)
+		})
+	}
+}
+
 func testRequestMatcherAlwaysPass(received *http.Request) (output string, differences int) {
 	return "PASS:  GOOD == GOOD", 0
 }
@@ -815,7 +1878,7 @@ func TestRequest_diff(t *testing.T) {
 			request: &Request{
 				method:   http.MethodPost,
 				url:      &url.URL{Path: "test.com/foo"},
-				matchers: []RequestMatcher{testRequestMatcherAlwaysFail},
+				matchers: []requestMatcher{newRequestMatcher(testRequestMatcherAlwaysFail)},
 			},
 			received: &http.Request{
 				Method: http.MethodPost,
@@ -829,7 +1892,7 @@ func TestRequest_diff(t *testing.T) {
 			request: &Request{
 				method:   http.MethodPost,
 				url:      &url.URL{Path: "test.com/foo"},
-				matchers: []RequestMatcher{testRequestMatcherAlwaysFail, testRequestMatcherSometimesPass},
+				matchers: []requestMatcher{newRequestMatcher(testRequestMatcherAlwaysFail), newRequestMatcher(testRequestMatcherSometimesPass)},
 			},
 			received: &http.Request{
 				Method: http.MethodPost,
@@ -870,7 +1933,7 @@ func TestRequest_diff(t *testing.T) {
 					Host:   "test.com",
 					Path:   "/foo",
 				},
-				matchers: []RequestMatcher{testRequestMatcherAlwaysPass, testRequestMatcherSometimesPass},
+				matchers: []requestMatcher{newRequestMatcher(testRequestMatcherAlwaysPass), newRequestMatcher(testRequestMatcherSometimesPass)},
 			},
 			received: &http.Request{
 				Method: http.MethodPut,
@@ -1181,6 +2244,49 @@ URL: https://test.com/foo?limit=1#back
 	Query: limit=1
 	Fragment: back
 Body: (X) (AnyBody)`,
+		},
+		{
+			name: "path-template",
+			request: &Request{
+				method: http.MethodGet,
+				url: &url.URL{
+					Scheme: "https",
+					Host:   "test.com",
+					Path:   "/users/{id}",
+				},
+				pathPattern: &pathTemplate{raw: "/users/{id}"},
+			},
+			want: `
+Method: GET
+URL: https://test.com/users/{id}
+	Scheme: https
+	Host: test.com
+	Path: /users/{id}
+	Query: (Missing)
+	Fragment: (Missing)
+Body: (0) (Missing)`,
+		},
+		{
+			name: "json-body",
+			request: &Request{
+				method: http.MethodPost,
+				url: &url.URL{
+					Scheme: "https",
+					Host:   "test.com",
+					Path:   "/foo",
+				},
+				body:     []byte(`{"foo": "bar"}`),
+				jsonBody: true,
+			},
+			want: `
+Method: POST
+URL: https://test.com/foo
+	Scheme: https
+	Host: test.com
+	Path: /foo
+	Query: (Missing)
+	Fragment: (Missing)
+Body: (JSON) (14) {"foo": "bar"}`,
 		},
 		{
 			name: "matcher",
@@ -1194,7 +2300,7 @@ Body: (X) (AnyBody)`,
 					Fragment: "back",
 				},
 				body:     []byte(testBody),
-				matchers: []RequestMatcher{testRequestMatcherAlwaysPass},
+				matchers: []requestMatcher{newRequestMatcher(testRequestMatcherAlwaysPass)},
 			},
 			want: `
 Method: GET
@@ -1219,7 +2325,7 @@ Matcher[0]: github.com/shawalli/httpmock.testRequestMatcherAlwaysPass`,
 					Fragment: "back",
 				},
 				body:     []byte(testBody),
-				matchers: []RequestMatcher{testRequestMatcherAlwaysPass, testRequestMatcherAlwaysFail},
+				matchers: []requestMatcher{newRequestMatcher(testRequestMatcherAlwaysPass), newRequestMatcher(testRequestMatcherAlwaysFail)},
 			},
 			want: `
 Method: GET
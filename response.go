@@ -1,11 +1,23 @@
 package httpmock
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"text/template"
+	"time"
 )
 
-var ErrWriteReturnBody = errors.New("error writing return body")
+var (
+	ErrWriteReturnBody  = errors.New("error writing return body")
+	ErrResponseCanceled = errors.New("response canceled")
+	ErrRenderTemplate   = errors.New("error rendering response template")
+)
 
 // ResponseWriter writes a [http.Response] and returns the number of bytes
 // written and whether or not the operation encountered an error.
@@ -31,6 +43,26 @@ type Response struct {
 	// Custom response writer that overrides statusCode, header, and body
 	// configurations.
 	writer ResponseWriter
+
+	// Minimum and maximum delay before the response is written. If they
+	// differ, a random delay between them is used.
+	minDelay time.Duration
+	maxDelay time.Duration
+
+	// Size of each chunk the body is written in, and the delay before each
+	// chunk after the first. A chunkSize of 0 writes the body all at once.
+	chunkSize  int
+	chunkDelay time.Duration
+
+	// Network failure to simulate instead of writing a well-formed response.
+	fault Fault
+
+	// Template that is rendered for each received request to produce the
+	// body, instead of using body.
+	template *template.Template
+
+	// Number of times the template has been rendered.
+	renders int
 }
 
 func newResponse(parent *Request, statusCode int, body []byte) *Response {
@@ -42,8 +74,41 @@ func newResponse(parent *Request, statusCode int, body []byte) *Response {
 	}
 }
 
-// lock is a convenience method to lock the grandparent [Mock]'s mutex.
+// clone creates a copy of a [Response] that does not share any mutable state
+// with the original.
+func (r *Response) clone() *Response {
+	c := *r
+	c.header = r.header.Clone()
+	if r.body != nil {
+		c.body = append([]byte{}, r.body...)
+	}
+	return &c
+}
+
+// StatusCode returns the HTTP status code that is used in the response.
+func (r *Response) StatusCode() int {
+	return r.statusCode
+}
+
+// Headers returns the headers that are used in the response.
+func (r *Response) Headers() http.Header {
+	return r.header.Clone()
+}
+
+// Body returns the body that is used in the response.
+func (r *Response) Body() []byte {
+	if r.body == nil {
+		return nil
+	}
+	return append([]byte{}, r.body...)
+}
+
+// lock is a convenience method to lock the grandparent [Mock]'s mutex. It
+// panics if the [Response] is a copy, which has no grandparent [Mock] to modify.
 func (r *Response) lock() {
+	if r.parent == nil || r.parent.parent == nil {
+		panic(msgModifySnapshot)
+	}
 	r.parent.parent.mutex.Lock()
 }
 
@@ -63,6 +128,60 @@ func (r *Response) Header(key string, value string, values ...string) *Response
 	return r
 }
 
+// Delay sets how long to wait before writing the response, simulating the time
+// to first byte of a slow server. If the request's context is done before the
+// delay has passed, the response is not written.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).RespondOK(nil).Delay(2 * time.Second)
+func (r *Response) Delay(d time.Duration) *Response {
+	return r.DelayBetween(d, d)
+}
+
+// DelayBetween sets a random delay, between minDelay and maxDelay inclusive,
+// to wait before writing the response. Random delays may be made reproducible
+// with [Mock.Seed].
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).RespondOK(nil).DelayBetween(100*time.Millisecond, 2*time.Second)
+func (r *Response) DelayBetween(minDelay time.Duration, maxDelay time.Duration) *Response {
+	if maxDelay < minDelay {
+		minDelay, maxDelay = maxDelay, minDelay
+	}
+
+	r.lock()
+	defer r.unlock()
+
+	r.minDelay = minDelay
+	r.maxDelay = maxDelay
+	return r
+}
+
+// DelayChunks writes the body in chunks of the provided size, flushing each
+// chunk and waiting for the provided delay before writing the next one. This
+// simulates a slow transfer after the first byte has been written.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).RespondOK(body).DelayChunks(512, 100*time.Millisecond)
+func (r *Response) DelayChunks(size int, d time.Duration) *Response {
+	r.lock()
+	defer r.unlock()
+
+	r.chunkSize = size
+	r.chunkDelay = d
+	return r
+}
+
+// Fault sets a network failure to simulate instead of writing a well-formed
+// response. Faults require a [http.ResponseWriter] that implements
+// [http.Hijacker], such as the one used by the default [Server] handler.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).RespondOK(body).Fault(FaultResetMidBody)
+func (r *Response) Fault(fault Fault) *Response {
+	r.lock()
+	defer r.unlock()
+
+	r.fault = fault
+	return r
+}
+
 // Once is a convenience method which indicates that the grandparent [Mock]
 // should only expect the parent request once.
 //
@@ -87,6 +206,141 @@ func (r *Response) Times(i int) *Request {
 	return r.parent.Times(i)
 }
 
+// Maybe is a convenience method which indicates that the grandparent [Mock]
+// should allow, but not require, the parent request.
+//
+//	Mock.On(http.MethodGet, "/health", nil).RespondOK(nil).Maybe()
+func (r *Response) Maybe() *Request {
+	return r.parent.Maybe()
+}
+
+// AtLeast is a convenience method which indicates that the grandparent [Mock]
+// should expect the parent request at least the indicated number of times.
+//
+//	Mock.On(http.MethodGet, "/jobs/1234", nil).RespondOK(nil).AtLeast(2)
+func (r *Response) AtLeast(i int) *Request {
+	return r.parent.AtLeast(i)
+}
+
+// AtMost is a convenience method which indicates that the grandparent [Mock]
+// should expect the parent request at most the indicated number of times.
+//
+//	Mock.On(http.MethodGet, "/jobs/1234", nil).RespondOK(nil).AtMost(3)
+func (r *Response) AtMost(i int) *Request {
+	return r.parent.AtMost(i)
+}
+
+// Between is a convenience method which indicates that the grandparent [Mock]
+// should expect the parent request at least minimum and at most maximum
+// number of times.
+//
+//	Mock.On(http.MethodGet, "/jobs/1234", nil).RespondOK(nil).Between(2, 5)
+func (r *Response) Between(minimum int, maximum int) *Request {
+	return r.parent.Between(minimum, maximum)
+}
+
+// ThenRespond adds a response to the parent [Request]'s sequence of responses.
+// Each time the parent [Request] is received, the next response in the
+// sequence is returned. After the last response has been returned, the
+// [SequencePolicy] set with [Request.AfterSequence] is used.
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).
+//		Respond(http.StatusServiceUnavailable, nil).
+//		ThenRespond(http.StatusServiceUnavailable, nil).
+//		ThenRespond(http.StatusOK, []byte(`{"foo": "bar"}`))
+func (r *Response) ThenRespond(statusCode int, body []byte) *Response {
+	return r.then(newResponse(r.parent, statusCode, body))
+}
+
+// ThenRespondOK is a convenience method that adds a response with the status
+// code 200 and the provided body to the parent [Request]'s sequence of
+// responses.
+func (r *Response) ThenRespondOK(body []byte) *Response {
+	return r.ThenRespond(http.StatusOK, body)
+}
+
+// ThenRespondJSON adds a response with a body marshaled from the provided value
+// as JSON to the parent [Request]'s sequence of responses. See
+// [Request.RespondJSON].
+func (r *Response) ThenRespondJSON(statusCode int, v any) *Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		r.parent.parent.fail("failed to marshal JSON response body. Error: %v\n", err)
+	}
+	return r.ThenRespond(statusCode, body).Header("Content-Type", "application/json")
+}
+
+// ThenRespondTemplate adds a response with a body rendered from a template to
+// the parent [Request]'s sequence of responses. See [Request.RespondTemplate].
+func (r *Response) ThenRespondTemplate(statusCode int, text string) *Response {
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		r.parent.parent.fail("failed to parse response template. Error: %v\n", err)
+	}
+
+	next := r.ThenRespond(statusCode, nil)
+
+	r.lock()
+	defer r.unlock()
+
+	next.template = tmpl
+	return next
+}
+
+// ThenRespondUsing adds a response that uses a custom [ResponseWriter] to the
+// parent [Request]'s sequence of responses.
+func (r *Response) ThenRespondUsing(writer ResponseWriter) *Response {
+	return r.then(&Response{parent: r.parent, writer: writer})
+}
+
+// then appends a response to the parent [Request]'s sequence of responses.
+func (r *Response) then(next *Response) *Response {
+	r.lock()
+	defer r.parent.validateSequenceCount()
+	defer r.unlock()
+
+	r.parent.sequence = append(r.parent.sequence, next)
+	return next
+}
+
+// AfterSequence is a convenience method which sets the [SequencePolicy] of the
+// parent [Request].
+func (r *Response) AfterSequence(policy SequencePolicy) *Request {
+	return r.parent.AfterSequence(policy)
+}
+
+// After is a convenience method which adds prerequisites to the parent
+// [Request]. See [Request.After].
+func (r *Response) After(others ...*Request) *Request {
+	return r.parent.After(others...)
+}
+
+// Run is a convenience method which sets a function that is called when the
+// parent [Request] is matched. See [Request.Run].
+func (r *Response) Run(fn func(*http.Request)) *Request {
+	return r.parent.Run(fn)
+}
+
+// WaitUntil is a convenience method which pauses the response until the
+// provided channel receives a value. See [Request.WaitUntil].
+func (r *Response) WaitUntil(w <-chan time.Time) *Request {
+	return r.parent.WaitUntil(w)
+}
+
+// NotifyOn is a convenience method which sends a value to the provided channel
+// when the parent [Request] is matched. See [Request.NotifyOn].
+func (r *Response) NotifyOn(ch chan<- struct{}) *Request {
+	return r.parent.NotifyOn(ch)
+}
+
+// Unset is a convenience method which removes the parent [Request] from the
+// expectations of the grandparent [Mock]. See [Request.Unset].
+//
+//	Mock.On(http.MethodGet, "/some/path", nil).RespondOK(nil).Unset()
+func (r *Response) Unset() *Request {
+	return r.parent.Unset()
+}
+
 // On chains a new expectation description onto the grandparent [Mock]. This
 // allows syntax like:
 //
@@ -102,30 +356,207 @@ func (r *Response) On(method string, path string, body []byte) *Request {
 // successfully written to the [http.ResponseWriter] are returned, as well as
 // any errors.
 //
+// If the request's context is done while waiting for a delay set with
+// [Response.Delay], [Response.DelayBetween], or [Response.DelayChunks], an
+// error wrapping [ErrResponseCanceled] is returned.
+//
+// If a [Fault] was set with [Response.Fault], the fault is simulated after any
+// delay before the response is written, instead of writing the response.
+//
 // Note: If [Request.RespondUsing] was previously called, all response
-// configurations are ignored except for the provided custom [ResponseWriter].
+// configurations are ignored except for the provided custom [ResponseWriter],
+// any delay before the response is written, and any [Fault].
 func (r *Response) Write(w http.ResponseWriter, req *http.Request) (int, error) {
+	// Copy the configuration so that the mutex is not held while waiting or
+	// writing.
 	r.lock()
-	defer r.unlock()
+	writer := r.writer
+	statusCode := r.statusCode
+	header := r.header.Clone()
+	body := r.body
+	delay := r.parent.parent.randomDuration(r.minDelay, r.maxDelay)
+	chunkSize, chunkDelay := r.chunkSize, r.chunkDelay
+	fault := r.fault
+	tmpl := r.template
+	if tmpl != nil {
+		r.renders++
+	}
+	renders := r.renders
+	r.unlock()
 
-	if r.writer != nil {
-		return r.writer(w, req)
+	ctx := context.Background()
+	if req != nil {
+		ctx = req.Context()
+	}
+
+	if err := sleepContext(ctx, delay); err != nil {
+		return 0, err
+	}
+
+	if tmpl != nil && writer == nil {
+		rendered, err := r.renderTemplate(tmpl, renders, req)
+		if err != nil {
+			return 0, err
+		}
+		body = rendered
+	}
+
+	if fault != FaultNone {
+		return writeFault(ctx, w, fault, statusCode, header, body)
+	}
+
+	if writer != nil {
+		return writer(w, req)
 	}
 
 	h := w.Header()
-	for key, values := range r.header {
+	for key, values := range header {
 		h[key] = values
 	}
 
-	w.WriteHeader(r.statusCode)
+	w.WriteHeader(statusCode)
 
-	if r.body != nil {
-		n, err := w.Write(r.body)
+	if body == nil {
+		return 0, nil
+	}
+
+	if chunkSize <= 0 {
+		n, err := w.Write(body)
 		if err != nil {
 			return n, ErrWriteReturnBody
 		}
 		return n, nil
 	}
 
-	return 0, nil
+	return writeChunks(ctx, w, body, chunkSize, chunkDelay)
+}
+
+// writeChunks writes a body in chunks of the provided size, flushing each
+// chunk and waiting for the provided delay before writing the next one.
+func writeChunks(ctx context.Context, w http.ResponseWriter, body []byte, size int, d time.Duration) (int, error) {
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	var n int
+	for i := 0; i < len(body); i += size {
+		if i > 0 {
+			if err := sleepContext(ctx, d); err != nil {
+				return n, err
+			}
+		}
+
+		written, err := w.Write(body[i:min(i+size, len(body))])
+		n += written
+		if err != nil {
+			return n, ErrWriteReturnBody
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	return n, nil
+}
+
+// sleepContext waits for the provided duration, or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrResponseCanceled, ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// writtenResponse is the response that was written for a received request, as
+// captured by the default [Server] handler or [Transport].
+type writtenResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// captureWriter is a [http.ResponseWriter] that captures the status code,
+// headers, and body that are written to the underlying [http.ResponseWriter].
+// It supports flushing and hijacking if the underlying [http.ResponseWriter]
+// does, and may be used with [http.ResponseController].
+type captureWriter struct {
+	http.ResponseWriter
+
+	statusCode int
+	header     http.Header
+	body       bytes.Buffer
+	hijacked   bool
+}
+
+// WriteHeader captures the status code and headers before writing them.
+func (w *captureWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 && !w.hijacked {
+		w.statusCode = statusCode
+		w.header = w.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write captures the body before writing it.
+func (w *captureWriter) Write(b []byte) (int, error) {
+	if w.hijacked {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements [http.Flusher]. It does nothing if the underlying
+// [http.ResponseWriter] cannot be flushed.
+func (w *captureWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError flushes the underlying [http.ResponseWriter], and returns an error
+// if it cannot be flushed. It is used by [http.ResponseController].
+func (w *captureWriter) FlushError() error {
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements [http.Hijacker]. It returns an error if the underlying
+// [http.ResponseWriter] cannot be hijacked. The response of a hijacked
+// connection is not captured.
+func (w *captureWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	w.hijacked = true
+	return conn, rw, nil
+}
+
+// Unwrap returns the underlying [http.ResponseWriter], so that
+// [http.ResponseController] may reach it.
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// written returns the captured response, or nil if the connection was
+// hijacked. If nothing was written, the response is the empty 200 that is
+// sent by a [http.Server].
+func (w *captureWriter) written() *writtenResponse {
+	if w.hijacked {
+		return nil
+	}
+	if w.statusCode == 0 {
+		return &writtenResponse{statusCode: http.StatusOK, header: w.Header().Clone()}
+	}
+	return &writtenResponse{statusCode: w.statusCode, header: w.header, body: w.body.Bytes()}
 }
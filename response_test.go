@@ -1,10 +1,14 @@
 package httpmock
 
 import (
+	"bufio"
+	"context"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -102,6 +106,41 @@ func TestResponse_Header(t *testing.T) {
 	}
 }
 
+func TestResponse_Getters(t *testing.T) {
+	// Setup
+	r := &Request{parent: new(Mock).Test(t)}
+	response := r.Respond(http.StatusCreated, []byte(testBody)).Header("next", "abcd")
+
+	// Test and Assertions
+	assert.Equal(t, http.StatusCreated, response.StatusCode())
+	assert.Equal(t, []byte(testBody), response.Body())
+	assert.Equal(t, http.Header{"next": []string{"abcd"}}, response.Headers())
+
+	// Modifying the returned values should not modify the response
+	response.Body()[0] = 'J'
+	response.Headers()["next"][0] = "efgh"
+	assert.Equal(t, []byte(testBody), response.body)
+	assert.Equal(t, []string{"abcd"}, response.header["next"])
+}
+
+func TestResponse_Delay(t *testing.T) {
+	// Setup
+	response := &Response{parent: &Request{parent: new(Mock).Test(t)}}
+
+	// Test and Assertions
+	response.Delay(time.Second)
+	assert.Equal(t, time.Second, response.minDelay)
+	assert.Equal(t, time.Second, response.maxDelay)
+
+	response.DelayBetween(2*time.Second, time.Second)
+	assert.Equal(t, time.Second, response.minDelay)
+	assert.Equal(t, 2*time.Second, response.maxDelay)
+
+	response.DelayChunks(512, time.Millisecond)
+	assert.Equal(t, 512, response.chunkSize)
+	assert.Equal(t, time.Millisecond, response.chunkDelay)
+}
+
 func TestResponse_Once(t *testing.T) {
 	// Setup
 	expected := &Request{parent: new(Mock).Test(t)}
@@ -138,6 +177,55 @@ func TestResponse_Times(t *testing.T) {
 	assert.Equal(t, 4, expected.repeatability)
 }
 
+func TestResponse_ThenRespond(t *testing.T) {
+	// Setup
+	expected := &Request{parent: new(Mock).Test(t)}
+	first := expected.Respond(http.StatusServiceUnavailable, nil)
+	writer := func(w http.ResponseWriter, r *http.Request) (int, error) { return 0, nil }
+
+	// Test
+	second := first.ThenRespond(http.StatusTooManyRequests, nil).Header("Retry-After", "1")
+	third := second.ThenRespondOK([]byte(testBody))
+	fourth := third.ThenRespondUsing(writer)
+
+	// Assertions
+	assert.Equal(t, []*Response{first, second, third, fourth}, append([]*Response{expected.response}, expected.sequence...))
+	assert.Equal(t, expected, second.parent)
+	assert.Equal(t, http.StatusTooManyRequests, second.statusCode)
+	assert.Equal(t, []string{"1"}, second.header["Retry-After"])
+	assert.Equal(t, http.StatusOK, third.statusCode)
+	assert.Equal(t, []byte(testBody), third.body)
+	assert.NotNil(t, fourth.writer)
+}
+
+func TestResponse_ThenRespondJSON(t *testing.T) {
+	// Setup
+	expected := &Request{parent: new(Mock).Test(t)}
+	first := expected.Respond(http.StatusServiceUnavailable, nil)
+
+	// Test
+	got := first.ThenRespondJSON(http.StatusOK, []string{"foo"})
+
+	// Assertions
+	assert.Equal(t, []*Response{first, got}, append([]*Response{expected.response}, expected.sequence...))
+	assert.Equal(t, http.StatusOK, got.statusCode)
+	assert.Equal(t, []byte(`["foo"]`), got.body)
+	assert.Equal(t, "application/json", got.header.Get("Content-Type"))
+}
+
+func TestResponse_AfterSequence(t *testing.T) {
+	// Setup
+	expected := &Request{parent: new(Mock).Test(t)}
+	response := Response{parent: expected}
+
+	// Test
+	got := response.AfterSequence(SequenceCycle)
+
+	// Assertions
+	assert.Equal(t, expected, got)
+	assert.Equal(t, SequenceCycle, expected.sequencePolicy)
+}
+
 func TestResponse_On(t *testing.T) {
 	// Setup
 	response := &Response{
@@ -281,3 +369,162 @@ func TestResponse_Write(t *testing.T) {
 		})
 	}
 }
+
+func TestResponse_Write_Delay(t *testing.T) {
+	// Setup
+	response := &Response{
+		parent:     &Request{parent: new(Mock).Test(t)},
+		statusCode: http.StatusOK,
+		body:       []byte(testBody),
+	}
+	response.Delay(50 * time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	start := time.Now()
+
+	// Test
+	gotN, gotErr := response.Write(recorder, nil)
+
+	// Assertions
+	assert.NoError(t, gotErr)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, len(testBody), gotN)
+	assert.Equal(t, testBody, recorder.Body.String())
+}
+
+func TestResponse_Write_DelayCanceled(t *testing.T) {
+	// Setup
+	response := &Response{
+		parent:     &Request{parent: new(Mock).Test(t)},
+		statusCode: http.StatusOK,
+		body:       []byte(testBody),
+	}
+	response.Delay(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req := mustNewRequest(http.NewRequestWithContext(ctx, http.MethodGet, "https://test.com/foo", http.NoBody))
+
+	recorder := httptest.NewRecorder()
+
+	// Test
+	gotN, gotErr := response.Write(recorder, req)
+
+	// Assertions
+	assert.ErrorIs(t, gotErr, ErrResponseCanceled)
+	assert.ErrorIs(t, gotErr, context.DeadlineExceeded)
+	assert.Zero(t, gotN)
+	assert.Zero(t, recorder.Body.Len())
+	assert.False(t, recorder.Flushed)
+}
+
+func TestResponse_Write_DelayChunks(t *testing.T) {
+	// Setup
+	response := &Response{
+		parent:     &Request{parent: new(Mock).Test(t)},
+		statusCode: http.StatusOK,
+		body:       []byte(testBody),
+	}
+	response.DelayChunks(5, 10*time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	start := time.Now()
+
+	// Test
+	gotN, gotErr := response.Write(recorder, nil)
+
+	// Assertions
+	assert.NoError(t, gotErr)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, len(testBody), gotN)
+	assert.Equal(t, testBody, recorder.Body.String())
+	assert.True(t, recorder.Flushed)
+}
+
+func TestResponse_Write_DelayChunksCanceled(t *testing.T) {
+	// Setup
+	response := &Response{
+		parent:     &Request{parent: new(Mock).Test(t)},
+		statusCode: http.StatusOK,
+		body:       []byte(testBody),
+	}
+	response.DelayChunks(5, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req := mustNewRequest(http.NewRequestWithContext(ctx, http.MethodGet, "https://test.com/foo", http.NoBody))
+
+	recorder := httptest.NewRecorder()
+
+	// Test
+	gotN, gotErr := response.Write(recorder, req)
+
+	// Assertions
+	assert.ErrorIs(t, gotErr, ErrResponseCanceled)
+	assert.Equal(t, 5, gotN)
+	assert.Equal(t, testBody[:5], recorder.Body.String())
+}
+
+// hijackRecorder is a [httptest.ResponseRecorder] that may be hijacked.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+
+	conn net.Conn
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.conn, bufio.NewReadWriter(bufio.NewReader(r.conn), bufio.NewWriter(r.conn)), nil
+}
+
+func TestCaptureWriter_Hijack(t *testing.T) {
+	// Setup
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	recorder := &hijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server}
+	w := &captureWriter{ResponseWriter: recorder}
+
+	// Test
+	conn, _, err := http.NewResponseController(w).Hijack()
+	w.WriteHeader(http.StatusTeapot)
+	w.Write([]byte(testBody))
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Same(t, server, conn)
+	assert.Nil(t, w.written())
+}
+
+func TestCaptureWriter_Hijack_NotSupported(t *testing.T) {
+	// Setup
+	recorder := httptest.NewRecorder()
+	w := &captureWriter{ResponseWriter: recorder}
+
+	// Test
+	conn, _, err := w.Hijack()
+	_, writeErr := w.Write([]byte(testBody))
+
+	// Assertions
+	assert.ErrorIs(t, err, http.ErrNotSupported)
+	assert.Nil(t, conn)
+	assert.NoError(t, writeErr)
+	if got := w.written(); assert.NotNil(t, got) {
+		assert.Equal(t, http.StatusOK, got.statusCode)
+		assert.Equal(t, []byte(testBody), got.body)
+	}
+}
+
+func TestCaptureWriter_Unwrap(t *testing.T) {
+	// Setup
+	recorder := httptest.NewRecorder()
+	w := &captureWriter{ResponseWriter: recorder}
+
+	// Test
+	err := http.NewResponseController(w).Flush()
+
+	// Assertions
+	assert.Same(t, recorder, w.Unwrap())
+	assert.NoError(t, err)
+	assert.True(t, recorder.Flushed)
+}
@@ -0,0 +1,174 @@
+package httpmock
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// MatchResult is the structured result of matching a [Request], or part of a
+// [Request], against a received [http.Request].
+type MatchResult struct {
+	// Name of the part of the request that was matched, such as "Method",
+	// "URL", "Body", or "header Accept".
+	Field string
+
+	// Formatted expected value.
+	Expected string
+
+	// Formatted actual value.
+	Actual string
+
+	// Free-form description of the result, used when it cannot be expressed
+	// with the actual and expected values alone.
+	Message string
+
+	// Whether or not the received request matched.
+	Pass bool
+
+	// Number of differences found. A result that does not pass is always
+	// counted as at least one difference.
+	Differences int
+
+	// Results of the nested parts of this result, such as the components of a
+	// URL or the matchers of a combinator.
+	Children []MatchResult
+
+	// Whether or not the result compares a JSON body, which is formatted
+	// differently in the diff of an unexpected request.
+	json bool
+}
+
+// ResultMatcher is used by the [Request.Matches] method to match a
+// [http.Request] with a structured result. The built-in matchers, such as
+// [HeaderEquals] and [AllOf], are [ResultMatcher]'s.
+type ResultMatcher func(received *http.Request) MatchResult
+
+// newMatchResult creates a [MatchResult] that compares an actual and expected
+// value.
+func newMatchResult(field string, actual string, expected string, pass bool) MatchResult {
+	result := MatchResult{
+		Field:    field,
+		Actual:   actual,
+		Expected: expected,
+		Pass:     pass,
+	}
+	if !pass {
+		result.Differences = 1
+	}
+	return result
+}
+
+// AdaptMatcher converts a [RequestMatcher] into a [ResultMatcher], such as to
+// combine it with [AllOf]. The output of the [RequestMatcher] is used as the
+// result's message, without any `PASS:` or `FAIL:` prefix, and the result
+// passes if there are no differences.
+func AdaptMatcher(fn RequestMatcher) ResultMatcher {
+	return func(received *http.Request) MatchResult {
+		output, differences := fn(received)
+
+		message := output
+		for _, prefix := range []string{"PASS:", "FAIL:"} {
+			if m, ok := strings.CutPrefix(output, prefix); ok {
+				message = strings.TrimLeft(m, " ")
+				break
+			}
+		}
+
+		return MatchResult{
+			Message:     message,
+			Pass:        differences == 0,
+			Differences: differences,
+		}
+	}
+}
+
+// differences returns the number of differences of a [MatchResult], counting
+// a result that does not pass as at least one difference.
+func (mr MatchResult) differences() int {
+	if !mr.Pass && mr.Differences == 0 {
+		return 1
+	}
+	return mr.Differences
+}
+
+// summary formats a [MatchResult] without its children, in the standard
+// `PASS:  <field>: <actual> == <expected>` and
+// `FAIL:  <field>: <actual> != <expected>` styles.
+func (mr MatchResult) summary() string {
+	status, eq := "PASS", fmtEqual
+	if !mr.Pass {
+		status, eq = "FAIL", fmtNotEqual
+	}
+
+	var parts []string
+	if mr.Field != "" {
+		parts = append(parts, mr.Field+":")
+	}
+	if mr.Message != "" {
+		parts = append(parts, mr.Message)
+	}
+	if mr.Actual != "" || mr.Expected != "" {
+		parts = append(parts, fmt.Sprintf("%s %s %s", mr.Actual, eq, mr.Expected))
+	}
+
+	return fmt.Sprintf("%s:  %s", status, strings.Join(parts, " "))
+}
+
+// formatChildren formats the children of a [MatchResult], one per line, with
+// each child's index and its own children indented beneath it.
+func (mr MatchResult) formatChildren() string {
+	var output string
+	for i, child := range mr.Children {
+		output += fmt.Sprintf("\n\t%d: %s", i, strings.ReplaceAll(child.String(), "\n", "\n\t"))
+	}
+	return output
+}
+
+// String formats a [MatchResult] and its children as text, with each child
+// indented beneath its parent.
+func (mr MatchResult) String() string {
+	return mr.summary() + mr.formatChildren()
+}
+
+// nestMatcherOutput formats the output of a nested [MatchResult] so that it is
+// indented beneath the output of its parent.
+func nestMatcherOutput(index int, output string) string {
+	return fmt.Sprintf("\n\t\t%d: %s", index, strings.ReplaceAll(output, "\n", "\n\t"))
+}
+
+// output formats a [MatchResult] in the style of a [RequestMatcher], with its
+// children nested beneath it, and returns its number of differences.
+func (mr MatchResult) output() (string, int) {
+	output := mr.summary()
+	for i, child := range mr.Children {
+		o, _ := child.output()
+		output += nestMatcherOutput(i, o)
+	}
+	return output, mr.differences()
+}
+
+// requestMatcher is a [ResultMatcher] registered on a [Request], along with
+// the name of the function it was created from.
+type requestMatcher struct {
+	name string
+
+	fn ResultMatcher
+}
+
+// funcName returns the name of a function.
+func funcName(fn any) string {
+	return runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+}
+
+// newRequestMatcher adapts a [RequestMatcher] for registration on a [Request].
+func newRequestMatcher(fn RequestMatcher) requestMatcher {
+	return requestMatcher{name: funcName(fn), fn: AdaptMatcher(fn)}
+}
+
+// newResultMatcher prepares a [ResultMatcher] for registration on a [Request].
+func newResultMatcher(fn ResultMatcher) requestMatcher {
+	return requestMatcher{name: funcName(fn), fn: fn}
+}
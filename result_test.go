@@ -0,0 +1,114 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher RequestMatcher
+		want    MatchResult
+	}{
+		{
+			name:    "pass",
+			matcher: testRequestMatcherAlwaysPass,
+			want:    MatchResult{Message: "GOOD == GOOD", Pass: true},
+		},
+		{
+			name:    "fail-with-pass-prefix",
+			matcher: testRequestMatcherAlwaysFail,
+			want:    MatchResult{Message: "BAD != GOOD", Differences: 1},
+		},
+		{
+			name: "no-prefix",
+			matcher: func(received *http.Request) (output string, differences int) {
+				return "match3", 2
+			},
+			want: MatchResult{Message: "match3", Differences: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got := AdaptMatcher(tt.matcher)(&http.Request{})
+
+			// Assertions
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMatchResult_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		result MatchResult
+		want   string
+	}{
+		{
+			name:   "pass",
+			result: newMatchResult("Method", "GET", "GET", true),
+			want:   "PASS:  Method: GET == GET",
+		},
+		{
+			name:   "fail",
+			result: newMatchResult("Method", "PUT", "GET", false),
+			want:   "FAIL:  Method: PUT != GET",
+		},
+		{
+			name:   "message",
+			result: MatchResult{Field: "Body", Message: "error reading body", Differences: 1},
+			want:   "FAIL:  Body: error reading body",
+		},
+		{
+			name:   "no-field",
+			result: MatchResult{Message: "GOOD == GOOD", Pass: true},
+			want:   "PASS:  GOOD == GOOD",
+		},
+		{
+			name: "children",
+			result: MatchResult{
+				Field:       "AllOf",
+				Message:     "1 of 2 passed",
+				Differences: 1,
+				Children: []MatchResult{
+					newMatchResult("header Accept", fmtMissing, fmtPresent, false),
+					{
+						Field:   "AnyOf",
+						Message: "1 of 1 passed",
+						Pass:    true,
+						Children: []MatchResult{
+							newMatchResult("cookie session", `"1234"`, `"1234"`, true),
+						},
+					},
+				},
+			},
+			want: "FAIL:  AllOf: 1 of 2 passed\n\t0: FAIL:  header Accept: (Missing) != (Present)\n\t1: PASS:  AnyOf: 1 of 1 passed\n\t\t0: PASS:  cookie session: \"1234\" == \"1234\"",
+		},
+		{
+			name:   "multi-line-child",
+			result: MatchResult{Field: "AllOf", Children: []MatchResult{{Message: "a\nb", Differences: 1}}, Differences: 1},
+			want:   "FAIL:  AllOf:\n\t0: FAIL:  a\n\tb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got := tt.result.String()
+
+			// Assertions
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMatchResult_differences(t *testing.T) {
+	assert.Equal(t, 0, MatchResult{Pass: true}.differences())
+	assert.Equal(t, 1, MatchResult{}.differences())
+	assert.Equal(t, 3, MatchResult{Differences: 3}.differences())
+}
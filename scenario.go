@@ -0,0 +1,172 @@
+package httpmock
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ScenarioStarted is the state of every scenario before any expectation has
+// moved it to a new state.
+const ScenarioStarted = "Started"
+
+// InScenario adds the [Request] to a named scenario. A scenario is a state
+// machine shared by its expectations: an expectation may only be matched while
+// the scenario is in the state set with [Request.WhenScenarioStateIs], and
+// moves the scenario to the state set with [Request.WillSetStateTo] when it is
+// matched. Every scenario starts in the [ScenarioStarted] state.
+//
+//	Mock.On(http.MethodGet, "/orders/1234", nil).Respond(http.StatusNotFound, nil).
+//		InScenario("order").WhenScenarioStateIs(ScenarioStarted)
+//	Mock.On(http.MethodPost, "/orders", AnyBody).Respond(http.StatusCreated, nil).
+//		InScenario("order").WillSetStateTo("Created")
+//	Mock.On(http.MethodGet, "/orders/1234", nil).RespondOK(order).
+//		InScenario("order").WhenScenarioStateIs("Created")
+func (r *Request) InScenario(name string) *Request {
+	r.lock()
+	defer r.unlock()
+
+	r.scenario = name
+	return r
+}
+
+// WhenScenarioStateIs indicates that the [Request] may only be matched while
+// its scenario is in the provided state. It requires [Request.InScenario].
+func (r *Request) WhenScenarioStateIs(state string) *Request {
+	r.lock()
+	defer r.unlock()
+
+	r.requiredState = state
+	return r
+}
+
+// WillSetStateTo indicates that the [Request]'s scenario should move to the
+// provided state each time the [Request] is matched. It requires
+// [Request.InScenario].
+func (r *Request) WillSetStateTo(state string) *Request {
+	r.lock()
+	defer r.unlock()
+
+	r.newState = state
+	return r
+}
+
+// InScenario is a convenience method which adds the parent [Request] to a
+// named scenario. See [Request.InScenario].
+func (r *Response) InScenario(name string) *Request {
+	return r.parent.InScenario(name)
+}
+
+// WhenScenarioStateIs is a convenience method which indicates that the parent
+// [Request] may only be matched while its scenario is in the provided state.
+// See [Request.WhenScenarioStateIs].
+func (r *Response) WhenScenarioStateIs(state string) *Request {
+	return r.parent.WhenScenarioStateIs(state)
+}
+
+// WillSetStateTo is a convenience method which indicates that the parent
+// [Request]'s scenario should move to the provided state when it is matched.
+// See [Request.WillSetStateTo].
+func (r *Response) WillSetStateTo(state string) *Request {
+	return r.parent.WillSetStateTo(state)
+}
+
+// matchScenario detects whether the [Request]'s scenario is in the state
+// required by the [Request]. The parent [Mock]'s mutex must be held by the
+// caller. The second return value is false if the [Request] does not require a
+// scenario state.
+func (r *Request) matchScenario() (MatchResult, bool) {
+	if r.scenario == "" || r.requiredState == "" || r.parent == nil {
+		return MatchResult{}, false
+	}
+
+	actual := r.parent.scenarioState(r.scenario)
+	result := newMatchResult("Scenario", actual, r.requiredState, actual == r.requiredState)
+	result.Message = fmt.Sprintf("(%s)", r.scenario)
+	return result, true
+}
+
+// setScenarioState moves the [Request]'s scenario to the state set with
+// [Request.WillSetStateTo], if any. The parent [Mock]'s mutex must be held by
+// the caller.
+func (r *Request) setScenarioState() {
+	if r.scenario == "" || r.newState == "" {
+		return
+	}
+	if r.parent.scenarios == nil {
+		r.parent.scenarios = map[string]string{}
+	}
+	r.parent.scenarios[r.scenario] = r.newState
+}
+
+// scenarioString formats the [Request]'s scenario and states.
+func (r *Request) scenarioString() string {
+	required := r.requiredState
+	if required == "" {
+		required = "(Any)"
+	}
+	output := fmt.Sprintf("%s: %s", r.scenario, required)
+	if r.newState != "" {
+		output += fmt.Sprintf(" -> %s", r.newState)
+	}
+	return output
+}
+
+// scenarioState returns the current state of a scenario. The [Mock]'s mutex
+// must be held by the caller.
+func (m *Mock) scenarioState(name string) string {
+	if state, ok := m.scenarios[name]; ok {
+		return state
+	}
+	return ScenarioStarted
+}
+
+// ScenarioState returns the current state of a scenario. A scenario that has
+// not moved to a new state is in the [ScenarioStarted] state.
+func (m *Mock) ScenarioState(name string) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.scenarioState(name)
+}
+
+// SetScenarioState moves a scenario to the provided state, such as to start a
+// test part of the way through a scenario.
+func (m *Mock) SetScenarioState(name string, state string) *Mock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.scenarios == nil {
+		m.scenarios = map[string]string{}
+	}
+	m.scenarios[name] = state
+	m.notifyChanged()
+	return m
+}
+
+// Scenarios returns a snapshot of the current state of every scenario that has
+// moved to a new state.
+func (m *Mock) Scenarios() map[string]string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return maps.Clone(m.scenarios)
+}
+
+// AssertScenarioState asserts that a scenario is in the provided state.
+func (m *Mock) AssertScenarioState(t mock.TestingT, name string, state string) bool {
+	if th, ok := t.(tHelper); ok {
+		th.Helper()
+	}
+
+	if actual := m.ScenarioState(name); actual != state {
+		return assert.Fail(
+			t,
+			"Scenario is not in the expected state",
+			fmt.Sprintf("Expected scenario %q to be in state %q, but it is in state %q", name, state, actual),
+		)
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+package httpmock
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Scenario(t *testing.T) {
+	// Setup
+	s := NewServerT(t)
+	s.On(http.MethodGet, "/orders/1234", nil).Respond(http.StatusNotFound, nil).
+		InScenario("order").WhenScenarioStateIs(ScenarioStarted)
+	s.On(http.MethodPost, "/orders", AnyBody).Respond(http.StatusCreated, nil).
+		InScenario("order").WhenScenarioStateIs(ScenarioStarted).WillSetStateTo("Created")
+	s.On(http.MethodGet, "/orders/1234", nil).RespondOK([]byte(`{"id": "1234"}`)).
+		InScenario("order").WhenScenarioStateIs("Created")
+	s.On(http.MethodDelete, "/orders/1234", nil).RespondNoContent().
+		InScenario("order").WhenScenarioStateIs("Created").WillSetStateTo(ScenarioStarted)
+
+	steps := []struct {
+		method     string
+		path       string
+		wantStatus int
+		wantState  string
+	}{
+		{method: http.MethodGet, path: "/orders/1234", wantStatus: http.StatusNotFound, wantState: ScenarioStarted},
+		{method: http.MethodPost, path: "/orders", wantStatus: http.StatusCreated, wantState: "Created"},
+		{method: http.MethodGet, path: "/orders/1234", wantStatus: http.StatusOK, wantState: "Created"},
+		{method: http.MethodDelete, path: "/orders/1234", wantStatus: http.StatusNoContent, wantState: ScenarioStarted},
+		{method: http.MethodGet, path: "/orders/1234", wantStatus: http.StatusNotFound, wantState: ScenarioStarted},
+	}
+
+	for i, step := range steps {
+		t.Run(fmt.Sprintf("%d-%s", i, step.method), func(t *testing.T) {
+			// Test
+			var body io.Reader = http.NoBody
+			if step.method == http.MethodPost {
+				body = strings.NewReader(testBody)
+			}
+			req := mustNewRequest(http.NewRequest(step.method, fmt.Sprintf("%s%s", s.URL, step.path), body))
+			got, err := s.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got.Body.Close()
+
+			// Assertions
+			assert.Equal(t, step.wantStatus, got.StatusCode)
+			s.Mock.AssertScenarioState(t, "order", step.wantState)
+		})
+	}
+}
+
+func TestRequest_matchScenario(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      string
+		wantOutput string
+		wantOK     bool
+	}{
+		{
+			name:       "match",
+			state:      "Created",
+			wantOutput: "PASS:  Scenario: (order) Created == Created",
+			wantOK:     true,
+		},
+		{
+			name:       "mismatch",
+			state:      "",
+			wantOutput: "FAIL:  Scenario: (order) Started != Created",
+			wantOK:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock)
+			if tt.state != "" {
+				m.SetScenarioState("order", tt.state)
+			}
+			r := m.On(http.MethodGet, "/orders/1234", nil).InScenario("order").WhenScenarioStateIs("Created")
+
+			// Test
+			got, gotOK := r.matchScenario()
+
+			// Assertions
+			assert.Equal(t, tt.wantOutput, got.String())
+			assert.Equal(t, tt.wantOK, gotOK)
+		})
+	}
+}
+
+func TestRequest_matchScenario_NoState(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	r := m.On(http.MethodPost, "/orders", nil).InScenario("order").WillSetStateTo("Created")
+
+	// Test
+	_, gotOK := r.matchScenario()
+
+	// Assertions
+	assert.False(t, gotOK)
+	assert.Contains(t, r.String(), "Scenario: order: (Any) -> Created")
+}
+
+func TestMock_Requested_ScenarioDiff(t *testing.T) {
+	// Setup
+	m := new(Mock)
+	m.On(http.MethodGet, "https://test.com/orders/1234", nil).RespondOK(nil).
+		InScenario("order").WhenScenarioStateIs("Created")
+
+	// Test
+	_, err := m.requested(mustNewRequest(http.NewRequest(http.MethodGet, "https://test.com/orders/1234", http.NoBody)))
+
+	// Assertions
+	assert.ErrorIs(t, err, ErrUnexpectedRequest)
+	assert.ErrorContains(t, err, "Scenario: order: Created")
+	assert.ErrorContains(t, err, "FAIL:  Scenario: (order) Started != Created")
+}
+
+func TestMock_AssertScenarioState(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	m := new(Mock)
+
+	// Test and Assertions
+	assert.True(t, m.AssertScenarioState(mockT, "order", ScenarioStarted))
+	assert.False(t, m.AssertScenarioState(mockT, "order", "Created"))
+	assert.Equal(t, 1, mockT.errorfCount)
+
+	m.SetScenarioState("order", "Created")
+	assert.True(t, m.AssertScenarioState(mockT, "order", "Created"))
+	assert.Equal(t, map[string]string{"order": "Created"}, m.Scenarios())
+
+	m.Reset()
+	assert.Equal(t, ScenarioStarted, m.ScenarioState("order"))
+}
@@ -1,9 +1,14 @@
 package httpmock
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"testing"
 )
 
 // Server simplifies the orchestration of a [Mock] inside a handler and server.
@@ -18,6 +23,24 @@ type Server struct {
 	// allowed to propagate to the parent process. If false, the panic will be
 	// printed and a 404 will be returned to the client.
 	ignorePanic bool
+
+	// Status code written to the client when a request cannot be handled,
+	// such as when it is unexpected.
+	unmatchedStatus int
+
+	// Cancels the base context of every request handled by the server, so
+	// that delayed and hanging responses are released when it is closed.
+	cancel context.CancelFunc
+
+	// Upstream that requests which do not match an expectation are proxied
+	// to, and the client used to proxy them.
+	upstream       *url.URL
+	upstreamClient *http.Client
+
+	// Exchanges that have been proxied to the upstream, and the file they are
+	// written to when the server is closed.
+	exchanges  []Exchange
+	recordFile string
 }
 
 // ServerConfig contains settings for configuring a [Server]. It is used with
@@ -28,10 +51,30 @@ type ServerConfig struct {
 
 	// Custom server handler
 	Handler http.HandlerFunc
+
+	// Status code written by the default handler when a request cannot be
+	// handled, such as when it is unexpected. Defaults to 404.
+	UnmatchedStatus int
+
+	// URL of an upstream server that requests which do not match an
+	// expectation are proxied to. Each proxied exchange is recorded, and may
+	// be accessed with [Server.Exchanges]. An invalid URL fails the test of
+	// [NewServerWithConfigT], or panics with [NewServerWithConfig].
+	Upstream string
+
+	// File that the exchanges proxied to the upstream are written to when the
+	// [Server] is closed, as a fixture that may be replayed with
+	// [Mock.LoadFixture]. It requires Upstream.
+	RecordFile string
 }
 
 // makeHandler creates a standard [http.HandlerFunc] that may be used by a
 // regular or TLS [Server] to log requests and write configured responses.
+//
+// The handler runs on a server goroutine, where failing the test is not
+// allowed. Instead, errors are recorded on the [Mock] and reported on the
+// test's goroutine by [Mock.AssertExpectations] or [Server.Close]. The error is
+// also written to the client, with the [Server]'s unmatched status code.
 func makeHandler(s *Server) http.HandlerFunc {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
@@ -48,9 +91,31 @@ func makeHandler(s *Server) http.HandlerFunc {
 				}
 			}()
 
-			response := s.Mock.Requested(r)
-			if _, err := response.Write(w, r); err != nil {
-				s.Mock.fail("failed to write response for request:\n%s\nwith error: %v", response.parent.String(), err)
+			response, seq, err := s.Mock.receive(r)
+			if err == nil && response == nil {
+				err = fmt.Errorf("%w: %s %s", ErrNoResponse, r.Method, r.URL)
+			}
+			if errors.Is(err, ErrResponseCanceled) {
+				return
+			}
+			if err != nil {
+				if !s.IsRecoverable() {
+					panic(err.Error())
+				}
+				s.Mock.recordFailure(err)
+				http.Error(w, err.Error(), s.unmatchedStatus)
+				return
+			}
+
+			// Capture the response as it is written, so that it may be exported
+			capture := &captureWriter{ResponseWriter: w}
+			_, err = response.Write(capture, r)
+			if errors.Is(err, ErrResponseCanceled) {
+				return
+			}
+			s.Mock.recordWritten(seq, capture.written())
+			if err != nil {
+				s.Mock.recordFailure(fmt.Errorf("failed to write response for request:\n%s\nwith error: %w", response.parent.String(), err))
 			}
 		},
 	)
@@ -58,29 +123,95 @@ func makeHandler(s *Server) http.HandlerFunc {
 
 // NewServer creates a new [Server] and associated [Mock].
 func NewServer() *Server {
-	s := &Server{Mock: new(Mock)}
-	s.Server = httptest.NewServer(http.HandlerFunc(makeHandler(s)))
-
-	return s
+	return NewServerWithConfig(ServerConfig{})
 }
 
+// NewServerWithConfig creates a new [Server] and associated [Mock], configured
+// with the provided [ServerConfig].
 func NewServerWithConfig(cfg ServerConfig) *Server {
-	s := &Server{Mock: new(Mock)}
+	return newServer(new(Mock), cfg)
+}
+
+// newServer creates and starts a new [Server] for a [Mock], configured with the
+// provided [ServerConfig]. The configuration is validated before the [Server]
+// is started, and any error fails the [Mock]'s test.
+func newServer(m *Mock, cfg ServerConfig) *Server {
+	s := &Server{Mock: m, unmatchedStatus: cfg.UnmatchedStatus, recordFile: cfg.RecordFile}
+	if s.unmatchedStatus == 0 {
+		s.unmatchedStatus = http.StatusNotFound
+	}
+	if cfg.Upstream != "" {
+		s.setUpstream(cfg.Upstream)
+	}
 
 	handler := cfg.Handler
 	if handler == nil {
 		handler = http.HandlerFunc(makeHandler(s))
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.Server = httptest.NewUnstartedServer(handler)
+	s.Server.Config.BaseContext = func(net.Listener) context.Context { return ctx }
+
 	if cfg.TLS {
-		s.Server = httptest.NewTLSServer(handler)
+		s.Server.StartTLS()
 	} else {
-		s.Server = httptest.NewServer(handler)
+		s.Server.Start()
 	}
 
 	return s
 }
 
+// NewServerT creates a new [Server] and associated [Mock] for a test. See
+// [NewServerWithConfigT].
+func NewServerT(t testing.TB) *Server {
+	t.Helper()
+
+	return NewServerWithConfigT(t, ServerConfig{})
+}
+
+// NewServerWithConfigT creates a new [Server] and associated [Mock] for a test,
+// configured with the provided [ServerConfig]. The [Mock] uses the test, and a
+// cleanup function is registered that closes the [Server], reports any failures
+// recorded by its handler, and asserts the [Mock]'s expectations.
+func NewServerWithConfigT(t testing.TB, cfg ServerConfig) *Server {
+	t.Helper()
+
+	s := newServer(new(Mock).Test(t), cfg)
+	t.Cleanup(func() {
+		t.Helper()
+
+		s.Close()
+		s.Mock.AssertExpectations(t)
+	})
+	return s
+}
+
+// Close cancels the context of any requests that are still being handled,
+// releasing responses that are delayed or hang, and then shuts down the
+// [Server], blocking until all requests have completed. If the [Server] was
+// configured with a record file, the exchanges proxied to its upstream are
+// then written to the file. Any failures recorded by the handler that have not
+// been reported yet are then reported to the test set with [Mock.Test].
+func (s *Server) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.Server.Close()
+
+	if err := s.writeRecordFile(); err != nil {
+		s.Mock.recordFailure(fmt.Errorf("failed to write record file %s: %w", s.recordFile, err))
+	}
+
+	s.Mock.mutex.Lock()
+	defer s.Mock.mutex.Unlock()
+	if s.Mock.test != nil {
+		s.Mock.reportFailures(s.Mock.test)
+	}
+}
+
 // NotRecoverable sets a [Server] as not recoverable, so that panics are allowed
 // to propagate to the main process. With the default handler, panics are caught
 // and printed to stdout, with a final 404 returned to the client.
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -73,6 +74,189 @@ func Test_NewServerWithConfig_CustomHandler(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
+func Test_NewServerT(t *testing.T) {
+	// Setup
+	s := NewServerT(t)
+	s.On(http.MethodGet, "/foo/1234", nil).RespondOK([]byte(testBody))
+
+	// Test
+	got, err := s.Client().Get(fmt.Sprintf("%s/foo/1234", s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Body.Close()
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+	assert.Same(t, t, s.Mock.test)
+}
+
+func Test_NewServerWithConfigT_Cleanup(t *testing.T) {
+	tests := []struct {
+		name            string
+		path            string
+		wantErrorfCount int
+	}{
+		{
+			name:            "expectations-met",
+			path:            "/foo/1234",
+			wantErrorfCount: 0,
+		},
+		{
+			name: "unexpected-request",
+			path: "/bar",
+			// Unexpected request and unmet expectation
+			wantErrorfCount: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			mockTB := new(MockTB)
+			s := NewServerWithConfigT(mockTB, ServerConfig{TLS: true})
+			s.On(http.MethodGet, "/foo/1234", nil).RespondOK(nil)
+			client := s.Client()
+
+			got, err := client.Get(fmt.Sprintf("%s%s", s.URL, tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got.Body.Close()
+
+			// Test
+			mockTB.runCleanups()
+
+			// Assertions
+			assert.Equal(t, tt.wantErrorfCount, mockTB.errorfCount)
+			assert.Zero(t, mockTB.failNowCount)
+
+			_, err = client.Get(fmt.Sprintf("%s%s", s.URL, tt.path))
+			assert.Error(t, err, "server should be closed")
+		})
+	}
+}
+
+func TestServer_Reset_Subtests(t *testing.T) {
+	// Setup
+	s := NewServerT(t)
+
+	for _, path := range []string{"/foo", "/bar"} {
+		t.Run(path, func(t *testing.T) {
+			// Setup
+			s.Mock.Reset()
+			s.On(http.MethodGet, path, nil).RespondOK([]byte(path)).Once()
+
+			// Test
+			got, err := s.Client().Get(fmt.Sprintf("%s%s", s.URL, path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotBody, err := io.ReadAll(got.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got.Body.Close()
+
+			// Assertions
+			assert.Equal(t, path, string(gotBody))
+			assert.Len(t, s.Mock.Requests, 1)
+			s.Mock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_Reset_WhileServing(t *testing.T) {
+	// Setup
+	s := NewServer()
+	defer s.Close()
+	s.On(http.MethodGet, "/foo", nil).RespondOK(nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 20 {
+			got, err := s.Client().Get(fmt.Sprintf("%s/foo", s.URL))
+			if err == nil {
+				got.Body.Close()
+			}
+		}
+	}()
+
+	// Test
+	for range 20 {
+		s.Mock.ResetRequests()
+		s.Mock.ResetExpectations()
+		s.On(http.MethodGet, "/foo", nil).RespondOK(nil).Unset()
+		s.On(http.MethodGet, "/foo", nil).RespondOK(nil)
+	}
+	<-done
+
+	// Assertions
+	assert.Len(t, s.Mock.ExpectedRequests, 1)
+}
+
+func TestServer_defaultHandler_NotifyOnWaitUntil(t *testing.T) {
+	// Setup
+	s := NewServerT(t)
+	arrived := make(chan struct{})
+	release := make(chan time.Time)
+	s.On(http.MethodGet, "/foo", nil).RespondOK([]byte(testBody)).NotifyOn(arrived).WaitUntil(release)
+
+	responses := make(chan *http.Response, 1)
+	go func() {
+		got, err := s.Client().Get(fmt.Sprintf("%s/foo", s.URL))
+		if err != nil {
+			t.Error(err)
+		}
+		responses <- got
+	}()
+
+	// Test
+	<-arrived
+	select {
+	case <-responses:
+		t.Fatal("unexpected response before it was released")
+	default:
+	}
+	close(release)
+
+	// Assertions
+	got := <-responses
+	if assert.NotNil(t, got) {
+		assert.Equal(t, http.StatusOK, got.StatusCode)
+		got.Body.Close()
+	}
+}
+
+func TestServer_defaultHandler_OnUnmatched(t *testing.T) {
+	// Setup
+	s := NewServerT(t)
+	s.Mock.TolerateUnmatched()
+	s.Mock.OnUnmatched().RespondUsing(func(w http.ResponseWriter, r *http.Request) (int, error) {
+		w.WriteHeader(http.StatusAccepted)
+		return w.Write([]byte(r.URL.Path))
+	})
+
+	// Test
+	got, err := s.Client().Get(fmt.Sprintf("%s/foo", s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotBody, err := io.ReadAll(got.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Body.Close()
+
+	// Assertions
+	assert.Equal(t, http.StatusAccepted, got.StatusCode)
+	assert.Equal(t, "/foo", string(gotBody))
+	assert.Empty(t, s.Mock.Failures())
+	assert.Len(t, s.Mock.UnmatchedRequests(), 1)
+	s.Mock.AssertRequested(t, http.MethodGet, "/foo", nil)
+}
+
 func TestServer_NotRecoverable(t *testing.T) {
 	// Setup
 	s := NewServer()
@@ -104,6 +288,105 @@ func TestServer_defaultHandler_NoMatch(t *testing.T) {
 	s.Mock.AssertNotRequested(t, http.MethodDelete, fmt.Sprintf("%s/foo/1234", s.URL), nil)
 }
 
+func TestServer_defaultHandler_NoMatchFailure(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	s := NewServer()
+	defer s.Close()
+	s.Mock.Test(mockT)
+	s.On(http.MethodGet, "/foo/1234", nil).RespondOK([]byte(testBody))
+
+	// Test
+	got, err := s.Client().Post(fmt.Sprintf("%s/foo/1234", s.URL), "text/plain", strings.NewReader(testBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotBody, err := io.ReadAll(got.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Body.Close()
+
+	// Assertions
+	assert.Equal(t, http.StatusNotFound, got.StatusCode)
+	assert.Contains(t, string(gotBody), "Unexpected Request")
+	assert.Zero(t, mockT.errorfCount)
+	assert.Zero(t, mockT.failNowCount)
+
+	failures := s.Mock.Failures()
+	if assert.Len(t, failures, 1) {
+		assert.ErrorIs(t, failures[0], ErrUnexpectedRequest)
+	}
+
+	assert.False(t, s.Mock.AssertNoFailures(mockT))
+	assert.Equal(t, 1, mockT.errorfCount)
+	assert.False(t, s.Mock.AssertNoFailures(mockT))
+	assert.Equal(t, 1, mockT.errorfCount)
+	assert.Zero(t, mockT.failNowCount)
+}
+
+func TestServer_defaultHandler_UnmatchedStatus(t *testing.T) {
+	// Setup
+	s := NewServerWithConfig(ServerConfig{UnmatchedStatus: http.StatusTeapot})
+	defer s.Close()
+	s.On(http.MethodGet, "/foo/1234", nil)
+
+	tests := []struct {
+		name      string
+		path      string
+		wantError error
+	}{
+		{
+			name:      "unexpected",
+			path:      "/bar",
+			wantError: ErrUnexpectedRequest,
+		},
+		{
+			name:      "no-response",
+			path:      "/foo/1234",
+			wantError: ErrNoResponse,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			got, err := s.Client().Get(fmt.Sprintf("%s%s", s.URL, tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got.Body.Close()
+
+			// Assertions
+			assert.Equal(t, http.StatusTeapot, got.StatusCode)
+			failures := s.Mock.Failures()
+			if assert.Len(t, failures, i+1) {
+				assert.ErrorIs(t, failures[i], tt.wantError)
+			}
+		})
+	}
+}
+
+func TestServer_Close_ReportsFailures(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	s := NewServer()
+	s.Mock.Test(mockT)
+
+	got, err := s.Client().Get(fmt.Sprintf("%s/foo", s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Body.Close()
+
+	// Test
+	s.Close()
+
+	// Assertions
+	assert.Equal(t, 1, mockT.errorfCount)
+	assert.Zero(t, mockT.failNowCount)
+}
+
 func TestServer_defaultHandler_AssertRequested(t *testing.T) {
 	// Setup
 	s := NewServer()
@@ -130,6 +413,30 @@ func TestServer_defaultHandler_AssertRequested(t *testing.T) {
 	s.Mock.AssertRequested(t, http.MethodGet, "/foo/1234", nil)
 }
 
+func TestServer_defaultHandler_RecordsMetadata(t *testing.T) {
+	// Setup
+	s := NewServerWithConfig(ServerConfig{TLS: true})
+	defer s.Close()
+	s.On(http.MethodGet, "/foo/1234", nil).RespondOK([]byte(testBody))
+
+	// Test
+	test := mustNewRequest(http.NewRequest(http.MethodGet, fmt.Sprintf("%s/foo/1234", s.URL), http.NoBody))
+	test.Header.Set("Idempotency-Key", "abcd")
+	got, err := s.Client().Do(test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Body.Close()
+
+	// Assertions
+	assert.Len(t, s.Mock.Requests, 1)
+	recorded := s.Mock.Requests[0]
+	assert.Equal(t, "abcd", recorded.Header().Get("Idempotency-Key"))
+	assert.Equal(t, s.Listener.Addr().String(), recorded.Host())
+	assert.NotEmpty(t, recorded.RemoteAddr())
+	assert.NotNil(t, recorded.TLS())
+}
+
 func TestServer_defaultHandler_AssertNotRequested(t *testing.T) {
 	// Setup
 	s := NewServer()
@@ -286,6 +593,57 @@ func TestServer_defaultHandler_AssertNumberOfRequests(t *testing.T) {
 	s.Mock.AssertNumberOfRequests(t, http.MethodDelete, "/foo/1234", 1)
 }
 
+func TestServer_defaultHandler_PathTemplate(t *testing.T) {
+	// Setup
+	s := NewServer()
+	defer s.Close()
+	s.On(http.MethodGet, "/users/{id}", nil).RespondUsing(func(w http.ResponseWriter, r *http.Request) (int, error) {
+		w.WriteHeader(http.StatusOK)
+		return w.Write([]byte(fmt.Sprintf(`{"id": %q}`, r.PathValue("id"))))
+	})
+
+	// Test
+	test := mustNewRequest(http.NewRequest(http.MethodGet, fmt.Sprintf("%s/users/456", s.URL), http.NoBody))
+	got, err := s.Client().Do(test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotBody, err := io.ReadAll(got.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Body.Close()
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+	assert.Equal(t, `{"id": "456"}`, string(gotBody))
+	s.Mock.AssertExpectations(t)
+}
+
+func TestServer_defaultHandler_DelayCanceled(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	s := NewServer()
+	s.Mock.Test(mockT)
+	s.On(http.MethodGet, "/foo", nil).RespondOK([]byte(testBody)).Delay(10 * time.Second)
+
+	client := s.Client()
+	client.Timeout = 50 * time.Millisecond
+
+	// Test
+	_, err := client.Get(fmt.Sprintf("%s/foo", s.URL))
+
+	start := time.Now()
+	s.Close()
+
+	// Assertions
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Zero(t, mockT.errorfCount)
+	assert.Zero(t, mockT.failNowCount)
+	s.Mock.AssertNumberOfRequests(t, http.MethodGet, "/foo", 1)
+}
+
 // TestSomething is the example given in the documentation.
 //
 // Let's keep it as a real test to ensure it actually works!
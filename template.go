@@ -0,0 +1,148 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateData is the data available to a response template set with
+// [Request.RespondTemplate]. It describes the received request.
+//
+//	{{.Method}} {{.Path}} {{index .Segments 1}} {{.PathValue "id"}}
+//	{{.Query.Get "page"}} {{.Header.Get "X-Request-Id"}} {{.JSON.name}}
+type TemplateData struct {
+	// Method of the received request.
+	Method string
+
+	// Path of the received request.
+	Path string
+
+	// Segments of the path, split on "/" and excluding empty segments, so that
+	// the path `/orders/1234` has segments `orders` and `1234`.
+	Segments []string
+
+	// Query values of the received request.
+	Query url.Values
+
+	// Headers of the received request.
+	Header http.Header
+
+	// Body of the received request.
+	Body string
+
+	// Body of the received request decoded as JSON, or nil if the body is not
+	// valid JSON. Numbers are decoded as [json.Number], so that they are
+	// rendered exactly as received.
+	JSON any
+
+	// The received request, used to look up path values.
+	request *http.Request
+}
+
+// newTemplateData creates the [TemplateData] for a received request. The
+// request's body is reset so that it may be read again.
+func newTemplateData(req *http.Request) (TemplateData, error) {
+	data := TemplateData{request: req}
+	if req == nil {
+		return data, nil
+	}
+
+	data.Method = req.Method
+	data.Header = req.Header
+	if req.URL != nil {
+		data.Path = req.URL.Path
+		data.Query = req.URL.Query()
+		for _, segment := range strings.Split(req.URL.Path, "/") {
+			if segment != "" {
+				data.Segments = append(data.Segments, segment)
+			}
+		}
+	}
+
+	body, err := SafeReadBody(req)
+	if err != nil {
+		return data, fmt.Errorf("%w: %w", ErrRenderTemplate, err)
+	}
+	data.Body = string(body)
+	if v, err := decodeJSON(body); err == nil {
+		data.JSON = v
+	}
+	return data, nil
+}
+
+// PathValue returns the value captured by a wildcard of a path template set
+// with [Mock.On], or an empty string if there is no such wildcard.
+func (d TemplateData) PathValue(name string) string {
+	if d.request == nil {
+		return ""
+	}
+	return d.request.PathValue(name)
+}
+
+// parseTemplateFuncs returns placeholders for the functions available to a
+// response template, which are used while parsing. See [Response.templateFuncs]
+// for the functions used while rendering.
+func parseTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"uuid":    func() string { return "" },
+		"now":     time.Now,
+		"counter": func() int { return 0 },
+		"json":    func(any) (string, error) { return "", nil },
+	}
+}
+
+// templateFuncs returns the functions available to a response template while
+// rendering the nth response:
+//
+//   - uuid returns a random version 4 UUID. It is reproducible if the [Mock]
+//     was seeded with [Mock.Seed].
+//   - now returns the current [time.Time].
+//   - counter returns the number of times the response has been rendered,
+//     starting at 1.
+//   - json marshals a value as JSON.
+func (r *Response) templateFuncs(n int) template.FuncMap {
+	return template.FuncMap{
+		"uuid": func() string {
+			r.lock()
+			defer r.unlock()
+			return r.parent.parent.randomUUID()
+		},
+		"now":     time.Now,
+		"counter": func() int { return n },
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}
+}
+
+// parseTemplate parses a response template.
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("response").Funcs(parseTemplateFuncs()).Parse(text)
+}
+
+// renderTemplate renders a response template for a received request. It is
+// the nth time that the response has been rendered.
+func (r *Response) renderTemplate(tmpl *template.Template, n int, req *http.Request) ([]byte, error) {
+	data, err := newTemplateData(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err = tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRenderTemplate, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Funcs(r.templateFuncs(n)).Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRenderTemplate, err)
+	}
+	return buf.Bytes(), nil
+}
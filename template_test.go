@@ -0,0 +1,204 @@
+package httpmock
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest_RespondTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "method-and-path",
+			template: `{{.Method}} {{.Path}}`,
+			want:     "POST /orders/1234/items",
+		},
+		{
+			name:     "segments",
+			template: `{{index .Segments 0}}/{{index .Segments 2}}`,
+			want:     "orders/items",
+		},
+		{
+			name:     "path-value",
+			template: `{{.PathValue "id"}}`,
+			want:     "1234",
+		},
+		{
+			name:     "query-and-header",
+			template: `{{.Query.Get "page"}} {{.Header.Get "X-Request-Id"}}`,
+			want:     "2 abc",
+		},
+		{
+			name:     "json-body",
+			template: `{"name": {{json .JSON.name}}, "count": {{.JSON.count}}}`,
+			want:     `{"name": "foo", "count": 12345678901234567890}`,
+		},
+		{
+			name:     "raw-body",
+			template: `{{.Body}}`,
+			want:     `{"name": "foo", "count": 12345678901234567890}`,
+		},
+		{
+			name:     "counter",
+			template: `{{counter}}-{{counter}}`,
+			want:     "1-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			m := new(Mock).Test(t)
+			m.On(http.MethodPost, "/orders/{id}/items", AnyBody).RespondTemplate(http.StatusCreated, tt.template)
+
+			received := mustNewRequest(http.NewRequest(http.MethodPost, "/orders/1234/items?page=2", strings.NewReader(`{"name": "foo", "count": 12345678901234567890}`)))
+			received.Header.Set("X-Request-Id", "abc")
+			recorder := httptest.NewRecorder()
+
+			// Test
+			_, err := m.Requested(received).Write(recorder, received)
+
+			// Assertions
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusCreated, recorder.Code)
+			assert.Equal(t, tt.want, recorder.Body.String())
+		})
+	}
+}
+
+func TestRequest_RespondTemplate_Counter(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(http.MethodPost, "/orders", AnyBody).RespondTemplate(http.StatusCreated, `{"id": {{counter}}}`)
+
+	// Test
+	var got []string
+	for range 3 {
+		received := mustNewRequest(http.NewRequest(http.MethodPost, "/orders", http.NoBody))
+		recorder := httptest.NewRecorder()
+		_, err := m.Requested(received).Write(recorder, received)
+		assert.NoError(t, err)
+		got = append(got, recorder.Body.String())
+	}
+
+	// Assertions
+	assert.Equal(t, []string{`{"id": 1}`, `{"id": 2}`, `{"id": 3}`}, got)
+}
+
+func TestRequest_RespondTemplate_UUID(t *testing.T) {
+	// Setup
+	render := func(m *Mock) string {
+		m.On(http.MethodPost, "/orders", AnyBody).RespondTemplate(http.StatusCreated, `{{uuid}}`)
+		received := mustNewRequest(http.NewRequest(http.MethodPost, "/orders", http.NoBody))
+		recorder := httptest.NewRecorder()
+		_, err := m.Requested(received).Write(recorder, received)
+		assert.NoError(t, err)
+		return recorder.Body.String()
+	}
+
+	// Test
+	got := render(new(Mock).Test(t).Seed(42))
+	gotSeeded := render(new(Mock).Test(t).Seed(42))
+	gotUnseeded := render(new(Mock).Test(t))
+
+	// Assertions
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), got)
+	assert.Equal(t, got, gotSeeded)
+	assert.NotEqual(t, got, gotUnseeded)
+}
+
+func TestRequest_RespondTemplate_Now(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(http.MethodGet, "/time", nil).RespondTemplate(http.StatusOK, `{{now.UTC.Format "2006-01-02T15:04:05Z07:00"}}`)
+
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "/time", http.NoBody))
+	recorder := httptest.NewRecorder()
+
+	// Test
+	_, err := m.Requested(received).Write(recorder, received)
+
+	// Assertions
+	assert.NoError(t, err)
+	got, err := time.Parse(time.RFC3339, recorder.Body.String())
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), got, time.Minute)
+}
+
+func TestRequest_RespondTemplate_ParseFail(t *testing.T) {
+	// Setup
+	mockT := new(MockTestingT)
+	r := &Request{parent: new(Mock).Test(mockT)}
+
+	// Test and Assertions
+	assert.PanicsWithValue(t, "FailNow was called", func() { r.RespondTemplate(http.StatusOK, `{{.Method`) })
+	assert.Equal(t, 1, mockT.errorfCount)
+}
+
+func TestResponse_Write_TemplateFail(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(http.MethodGet, "/orders", nil).RespondTemplate(http.StatusOK, `{{index .Segments 5}}`)
+
+	received := mustNewRequest(http.NewRequest(http.MethodGet, "/orders", http.NoBody))
+	recorder := httptest.NewRecorder()
+
+	// Test
+	n, err := m.Requested(received).Write(recorder, received)
+
+	// Assertions
+	assert.ErrorIs(t, err, ErrRenderTemplate)
+	assert.Zero(t, n)
+	assert.Zero(t, recorder.Body.Len())
+}
+
+func TestResponse_Write_TemplateFailToReadBody(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(http.MethodPost, "/orders", AnyBody).RespondTemplate(http.StatusOK, `{{.Body}}`)
+
+	response := m.Requested(mustNewRequest(http.NewRequest(http.MethodPost, "/orders", strings.NewReader(testBody))))
+	received := mustNewRequest(http.NewRequest(http.MethodPost, "/orders", iotest.ErrReader(errors.New("read failed"))))
+	recorder := httptest.NewRecorder()
+
+	// Test
+	n, err := response.Write(recorder, received)
+
+	// Assertions
+	assert.ErrorIs(t, err, ErrRenderTemplate)
+	assert.ErrorContains(t, err, "read failed")
+	assert.Zero(t, n)
+	assert.Zero(t, recorder.Body.Len())
+}
+
+func TestResponse_ThenRespondTemplate(t *testing.T) {
+	// Setup
+	m := new(Mock).Test(t)
+	m.On(http.MethodGet, "/jobs/{id}", nil).
+		Respond(http.StatusAccepted, nil).
+		ThenRespondTemplate(http.StatusOK, `{"id": "{{.PathValue "id"}}", "status": "done"}`)
+
+	// Test
+	var got []string
+	for range 2 {
+		received := mustNewRequest(http.NewRequest(http.MethodGet, "/jobs/1234", http.NoBody))
+		recorder := httptest.NewRecorder()
+		_, err := m.Requested(received).Write(recorder, received)
+		assert.NoError(t, err)
+		got = append(got, recorder.Body.String())
+	}
+
+	// Assertions
+	assert.Equal(t, []string{"", `{"id": "1234", "status": "done"}`}, got)
+}
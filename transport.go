@@ -0,0 +1,211 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+)
+
+var ErrNoResponse = errors.New("no response configured for request")
+
+// Transport is a [http.RoundTripper] that routes requests to a [Mock] and
+// builds a [http.Response] from the matched [Response], without starting a
+// server. Requests may be routed to a different [Mock] for each host, so that
+// a single [http.Client] can talk to several fake upstreams.
+//
+// Responses are written to an in-memory recorder, so any [ResponseWriter] set
+// with [Request.RespondUsing] is supported. A [Fault] is not supported, since
+// there is no connection to fail.
+type Transport struct {
+	// Mock that handles requests for any host without its own [Mock].
+	Mock *Mock
+
+	// Mocks that handle requests for specific hosts.
+	hosts map[string]*Mock
+
+	// Test struct that is set on every Mock, including those created by
+	// [Transport.Host].
+	test mock.TestingT
+
+	mutex sync.Mutex
+}
+
+// NewTransport creates a new [Transport] and associated [Mock].
+func NewTransport() *Transport {
+	return &Transport{Mock: new(Mock)}
+}
+
+// NewTransportT creates a new [Transport] and associated [Mock] for a test.
+// Every [Mock] of the [Transport] uses the test, and a cleanup function is
+// registered that asserts their expectations when the test completes.
+func NewTransportT(t testing.TB) *Transport {
+	t.Helper()
+
+	tr := NewTransport().Test(t)
+	t.Cleanup(func() {
+		t.Helper()
+
+		tr.AssertExpectations(t)
+	})
+	return tr
+}
+
+// Host returns the [Mock] that handles requests for a host, creating it if
+// it does not exist yet. The host may include a port, in which case only
+// requests to that port are routed to the [Mock].
+//
+//	Transport.Host("api.example.com").On(http.MethodGet, "/users/1234", nil)
+func (t *Transport) Host(host string) *Mock {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.hosts == nil {
+		t.hosts = map[string]*Mock{}
+	}
+	m, ok := t.hosts[host]
+	if !ok {
+		m = new(Mock)
+		if t.test != nil {
+			m.Test(t.test)
+		}
+		t.hosts[host] = m
+	}
+	return m
+}
+
+// mockFor returns the [Mock] that handles requests for a host. A [Mock] for
+// the exact host is preferred, followed by a [Mock] for its hostname without
+// the port, and finally the default [Mock].
+func (t *Transport) mockFor(host string) *Mock {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if m, ok := t.hosts[host]; ok {
+		return m
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		if m, ok := t.hosts[hostname]; ok {
+			return m
+		}
+	}
+	return t.Mock
+}
+
+// mocks returns every [Mock] of the [Transport].
+func (t *Transport) mocks() []*Mock {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var mocks []*Mock
+	if t.Mock != nil {
+		mocks = append(mocks, t.Mock)
+	}
+	for _, m := range t.hosts {
+		mocks = append(mocks, m)
+	}
+	return mocks
+}
+
+// RoundTrip routes a request to the [Mock] for its host and returns the
+// matched [Response]. It implements [http.RoundTripper].
+//
+// As with a [Server], the [Mock] receives a request whose URL only contains
+// the path, query, and fragment. The host is available from the request's
+// Host field.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	m := t.mockFor(req.URL.Host)
+	if m == nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("httpmock: no mock for host %q", req.URL.Host)
+	}
+
+	// A RoundTripper must not modify the request, so match a copy of it that
+	// looks like the request a server would receive
+	received := req.Clone(req.Context())
+	if received.Body == nil {
+		received.Body = http.NoBody
+	}
+	if received.Host == "" {
+		received.Host = req.URL.Host
+	}
+	received.URL.Scheme = ""
+	received.URL.Host = ""
+	received.URL.User = nil
+	received.RequestURI = received.URL.RequestURI()
+
+	// RoundTrip may be called from any goroutine, so failures are recorded
+	// rather than failing the test
+	response, seq, err := m.receive(received)
+	if err == nil && response == nil {
+		err = fmt.Errorf("%w: %s %s", ErrNoResponse, req.Method, req.URL)
+	}
+	if err != nil {
+		if !errors.Is(err, ErrResponseCanceled) {
+			m.recordFailure(err)
+		}
+		return nil, err
+	}
+
+	recorder := httptest.NewRecorder()
+	if _, err := response.Write(recorder, received); err != nil {
+		if !errors.Is(err, ErrResponseCanceled) {
+			m.recordFailure(fmt.Errorf("failed to write response for request:\n%s\nwith error: %w", response.parent.String(), err))
+		}
+		return nil, err
+	}
+
+	resp := recorder.Result()
+	resp.Request = req
+	m.recordWritten(seq, &writtenResponse{statusCode: resp.StatusCode, header: resp.Header.Clone(), body: recorder.Body.Bytes()})
+	return resp, nil
+}
+
+// Client creates a [http.Client] that uses the [Transport].
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Test sets the test struct variable of every [Mock] of the [Transport],
+// including any that are created afterward with [Transport.Host].
+func (t *Transport) Test(test mock.TestingT) *Transport {
+	t.mutex.Lock()
+	t.test = test
+	t.mutex.Unlock()
+
+	for _, m := range t.mocks() {
+		m.Test(test)
+	}
+	return t
+}
+
+// On is a convenience method to invoke the [Mock.On] method of the default
+// [Mock].
+//
+//	Transport.On(http.MethodDelete, "/some/path/1234")
+func (t *Transport) On(method string, URL string, body []byte) *Request {
+	return t.Mock.On(method, URL, body)
+}
+
+// AssertExpectations asserts that everything specified with [Mock.On] was in
+// fact requested as expected, for every [Mock] of the [Transport].
+func (t *Transport) AssertExpectations(test mock.TestingT) bool {
+	if th, ok := test.(tHelper); ok {
+		th.Helper()
+	}
+
+	ok := true
+	for _, m := range t.mocks() {
+		if !m.AssertExpectations(test) {
+			ok = false
+		}
+	}
+	return ok
+}
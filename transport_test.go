@@ -0,0 +1,216 @@
+package httpmock
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransport(t *testing.T) {
+	// Test
+	tr := NewTransport()
+
+	// Assertions
+	assert.NotNil(t, tr.Mock)
+	assert.Equal(t, tr, tr.Client().Transport)
+}
+
+func TestNewTransportT(t *testing.T) {
+	// Setup
+	mockTB := new(MockTB)
+
+	// Test
+	tr := NewTransportT(mockTB)
+	tr.Host("users.test.com").On(http.MethodGet, "/", nil).RespondOK(nil)
+
+	// Assertions
+	assert.Same(t, mockTB, tr.Host("users.test.com").test)
+	mockTB.runCleanups()
+	assert.Equal(t, 1, mockTB.errorfCount)
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	// Setup
+	tr := NewTransport().Test(t)
+	tr.On(http.MethodPost, "/foo?page=2", []byte(testBody)).
+		Respond(http.StatusCreated, []byte(`{"id": "1234"}`)).
+		Header("Location", "/foo/1234")
+
+	req := mustNewRequest(http.NewRequest(http.MethodPost, "https://test.com/foo?page=2", strings.NewReader(testBody)))
+
+	// Test
+	got, err := tr.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotBody, err := io.ReadAll(got.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Body.Close()
+
+	// Assertions
+	assert.Equal(t, http.StatusCreated, got.StatusCode)
+	assert.Equal(t, "/foo/1234", got.Header.Get("Location"))
+	assert.Equal(t, `{"id": "1234"}`, string(gotBody))
+	assert.Equal(t, req, got.Request)
+	assert.Equal(t, "https://test.com/foo?page=2", req.URL.String())
+
+	tr.AssertExpectations(t)
+	assert.Len(t, tr.Mock.Requests, 1)
+	assert.Equal(t, "test.com", tr.Mock.Requests[0].Host())
+	assert.Equal(t, []byte(testBody), tr.Mock.Requests[0].Body())
+}
+
+func TestTransport_RoundTrip_RespondUsing(t *testing.T) {
+	// Setup
+	tr := NewTransport().Test(t)
+	tr.On(http.MethodGet, "/users/{id}", nil).RespondUsing(func(w http.ResponseWriter, r *http.Request) (int, error) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return w.Write([]byte(fmt.Sprintf(`{"id": %q}`, r.PathValue("id"))))
+	})
+
+	// Test
+	got, err := tr.Client().Get("https://test.com/users/456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotBody, err := io.ReadAll(got.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Body.Close()
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.Equal(t, `{"id": "456"}`, string(gotBody))
+}
+
+func TestTransport_Host(t *testing.T) {
+	// Setup
+	tr := NewTransport().Test(t)
+	tr.On(http.MethodGet, "/", nil).Respond(http.StatusTeapot, nil)
+	tr.Host("users.test.com").On(http.MethodGet, "/", nil).RespondOK([]byte("users"))
+	tr.Host("orders.test.com:8443").On(http.MethodGet, "/", nil).RespondOK([]byte("orders"))
+
+	tests := []struct {
+		url        string
+		wantStatus int
+		wantBody   string
+	}{
+		{url: "https://users.test.com/", wantStatus: http.StatusOK, wantBody: "users"},
+		{url: "https://users.test.com:8443/", wantStatus: http.StatusOK, wantBody: "users"},
+		{url: "https://orders.test.com:8443/", wantStatus: http.StatusOK, wantBody: "orders"},
+		{url: "https://orders.test.com/", wantStatus: http.StatusTeapot, wantBody: ""},
+		{url: "https://other.test.com/", wantStatus: http.StatusTeapot, wantBody: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			// Test
+			got, err := tr.Client().Get(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotBody, err := io.ReadAll(got.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got.Body.Close()
+
+			// Assertions
+			assert.Equal(t, tt.wantStatus, got.StatusCode)
+			assert.Equal(t, tt.wantBody, string(gotBody))
+		})
+	}
+
+	assert.Same(t, tr.Host("users.test.com"), tr.Host("users.test.com"))
+	assert.Len(t, tr.Host("users.test.com").Requests, 2)
+	assert.Len(t, tr.Host("orders.test.com:8443").Requests, 1)
+	assert.Len(t, tr.Mock.Requests, 2)
+	tr.AssertExpectations(t)
+}
+
+func TestTransport_AssertExpectations(t *testing.T) {
+	// Setup
+	tr := NewTransport()
+	tr.On(http.MethodGet, "/", nil).RespondOK(nil)
+	tr.Host("users.test.com").On(http.MethodGet, "/", nil).RespondOK(nil)
+
+	mockT := new(MockTestingT)
+
+	// Test and Assertions
+	assert.False(t, tr.AssertExpectations(mockT))
+
+	_, err := tr.Client().Get("https://test.com/")
+	assert.NoError(t, err)
+	assert.False(t, tr.AssertExpectations(mockT))
+
+	_, err = tr.Client().Get("https://users.test.com/")
+	assert.NoError(t, err)
+	assert.True(t, tr.AssertExpectations(mockT))
+}
+
+func TestTransport_RoundTrip_NoMock(t *testing.T) {
+	// Setup
+	tr := &Transport{}
+	tr.Host("users.test.com").Test(t)
+
+	// Test
+	_, err := tr.Client().Get("https://test.com/")
+
+	// Assertions
+	assert.ErrorContains(t, err, `no mock for host "test.com"`)
+}
+
+func TestTransport_RoundTrip_NoResponse(t *testing.T) {
+	// Setup
+	tr := NewTransport().Test(t)
+	tr.On(http.MethodGet, "/", nil)
+
+	// Test
+	_, err := tr.Client().Get("https://test.com/")
+
+	// Assertions
+	assert.ErrorIs(t, err, ErrNoResponse)
+}
+
+func TestTransport_RoundTrip_Fault(t *testing.T) {
+	// Setup
+	tr := NewTransport().Test(t)
+	tr.On(http.MethodGet, "/", nil).RespondFault(FaultCloseConnection)
+
+	// Test
+	_, err := tr.Client().Get("https://test.com/")
+
+	// Assertions
+	assert.ErrorIs(t, err, ErrFaultUnsupported)
+	if failures := tr.Mock.Failures(); assert.Len(t, failures, 1) {
+		assert.ErrorIs(t, failures[0], ErrFaultUnsupported)
+	}
+}
+
+func TestTransport_RoundTrip_DelayCanceled(t *testing.T) {
+	// Setup
+	tr := NewTransport().Test(t)
+	tr.On(http.MethodGet, "/", nil).RespondOK(nil).Delay(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req := mustNewRequest(http.NewRequestWithContext(ctx, http.MethodGet, "https://test.com/", http.NoBody))
+
+	// Test
+	_, err := tr.Client().Do(req)
+
+	// Assertions
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, tr.Mock.Failures())
+}